@@ -0,0 +1,164 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di_test
+
+import (
+	"testing"
+
+	"github.com/gozix/di"
+
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	Leaf struct{}
+
+	Node[T any] struct {
+		Dep         T
+		Controllers []Controller
+	}
+)
+
+func NewNode[T any](dep T, controllers []Controller) *Node[T] {
+	return &Node[T]{Dep: dep, Controllers: controllers}
+}
+
+func newBenchmarkContainer(b *testing.B, unshared bool) di.Container {
+	var options = []di.ProvideOption{
+		di.Constraint(1, di.WithTags("controller")),
+	}
+
+	if unshared {
+		options = append(options, di.Unshared())
+	}
+
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Controller)), di.Tags{{Name: "controller"}}),
+		di.Provide(NewBazController, di.As(new(Controller)), di.Tags{{Name: "controller"}}),
+		di.Provide(func() *Leaf { return &Leaf{} }),
+		di.Provide(NewNode[*Leaf], options...),
+		di.Provide(NewNode[*Node[*Leaf]], options...),
+		di.Provide(NewNode[*Node[*Node[*Leaf]]], options...),
+		di.Provide(NewNode[*Node[*Node[*Node[*Leaf]]]], options...),
+		di.Provide(NewNode[*Node[*Node[*Node[*Node[*Leaf]]]]], options...),
+		di.Provide(NewNode[*Node[*Node[*Node[*Node[*Node[*Leaf]]]]]], options...),
+	)
+
+	require.NoError(b, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(b, err)
+
+	b.Cleanup(func() {
+		require.NoError(b, ctn.Close())
+	})
+
+	return ctn
+}
+
+func BenchmarkContainer_ResolveDeepUnshared(b *testing.B) {
+	var (
+		ctn    = newBenchmarkContainer(b, true)
+		target *Node[*Node[*Node[*Node[*Node[*Node[*Leaf]]]]]]
+	)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := ctn.Resolve(&target); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkContainer_ResolveShared(b *testing.B) {
+	var (
+		ctn    = newBenchmarkContainer(b, false)
+		target *Node[*Node[*Node[*Node[*Node[*Node[*Leaf]]]]]]
+	)
+
+	require.NoError(b, ctn.Resolve(&target))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := ctn.Resolve(&target); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkContainer_CallDeepUnshared(b *testing.B) {
+	var (
+		ctn = newBenchmarkContainer(b, true)
+		fn  = func(*Node[*Node[*Node[*Node[*Node[*Node[*Leaf]]]]]], *Leaf) {}
+	)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := ctn.Call(fn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkContainer_ResolveSharedParallel(b *testing.B) {
+	var ctn = newBenchmarkContainer(b, false)
+	require.NoError(b, ctn.Resolve(new(*Node[*Node[*Node[*Node[*Node[*Node[*Leaf]]]]]])))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var target *Node[*Node[*Node[*Node[*Node[*Node[*Leaf]]]]]]
+		for pb.Next() {
+			if err := ctn.Resolve(&target); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkContainer_ResolveDeepUnsharedTyped(b *testing.B) {
+	var options = []di.ProvideOption{
+		di.Constraint(1, di.WithTags("controller")),
+		di.Unshared(),
+	}
+
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Controller)), di.Tags{{Name: "controller"}}),
+		di.Provide(NewBazController, di.As(new(Controller)), di.Tags{{Name: "controller"}}),
+		di.Provide0(func() *Leaf { return &Leaf{} }),
+		di.Provide2(NewNode[*Leaf], options...),
+		di.Provide2(NewNode[*Node[*Leaf]], options...),
+		di.Provide2(NewNode[*Node[*Node[*Leaf]]], options...),
+		di.Provide2(NewNode[*Node[*Node[*Node[*Leaf]]]], options...),
+		di.Provide2(NewNode[*Node[*Node[*Node[*Node[*Leaf]]]]], options...),
+		di.Provide2(NewNode[*Node[*Node[*Node[*Node[*Node[*Leaf]]]]]], options...),
+	)
+
+	require.NoError(b, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(b, err)
+
+	var target *Node[*Node[*Node[*Node[*Node[*Node[*Leaf]]]]]]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err = ctn.Resolve(&target); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
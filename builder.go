@@ -7,6 +7,8 @@ package di
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/gozix/di/internal/compiler"
@@ -16,15 +18,20 @@ import (
 
 // builder implements the Builder interface.
 type builder struct {
-	defs definitions
-	mux  sync.Mutex
-	seq  int
+	defs     definitions
+	mux      sync.Mutex
+	order    Modifier
+	parallel int
+	seq      int
+	strict   bool
+	warn     func(err error)
 }
 
 // NewBuilder is builder constructor.
 func NewBuilder(options ...BuilderOption) (_ Builder, err error) {
 	var b = &builder{
-		defs: definitions{},
+		defs:  definitions{},
+		order: ByPriority(),
 	}
 
 	return b, b.Apply(options...)
@@ -71,10 +78,119 @@ func (b *builder) Autowire(value Type, options ...ProvideOption) (err error) {
 		return fmt.Errorf("%s : %w", def.frame, err)
 	}
 
+	if err = validateDependencies(def.compiler); err != nil {
+		return fmt.Errorf("%s : %w", def.frame, err)
+	}
+
 	return b.add(def)
 }
 
+func (b *builder) Override(target Type, value Constructor, options ...ProvideOption) (err error) {
+	var (
+		frame     = runtime.Caller(0)
+		modifiers []Modifier
+	)
+
+	for _, o := range options {
+		switch o := o.(type) {
+		case *callerOption:
+			frame = o.frame
+		case *replacingOption:
+			modifiers = append(modifiers, o.modifiers...)
+		}
+	}
+
+	var rt = reflect.TypeOf(target)
+	if rt == nil {
+		return fmt.Errorf("%s : %w", frame, ErrInvalidType)
+	}
+
+	if value == nil {
+		return fmt.Errorf("%s : constructor %w", frame, ErrIsNil)
+	}
+
+	var defs []*definition
+	if defs, err = b.provide(value, frame, options); err != nil {
+		return err
+	}
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if len(b.defs[rt]) == 0 && rt.Kind() == reflect.Pointer && len(b.defs[rt.Elem()]) > 0 {
+		rt = rt.Elem()
+	}
+
+	var replaced = b.defs.find(rt, modifiers)
+	if len(replaced) == 0 {
+		return fmt.Errorf("%s : %w", frame, NewTypeError(rt, ErrDoesNotExist))
+	}
+
+	var (
+		ids     = make(map[int]bool, len(replaced))
+		aliases []any
+	)
+
+	if rt.Kind() == reflect.Interface {
+		aliases = append(aliases, reflect.New(rt).Interface())
+	}
+
+	for _, def := range replaced {
+		ids[def.id] = true
+		aliases = append(aliases, def.aliases...)
+	}
+
+	var found = false
+	for _, def := range defs {
+		for _, alias := range aliases {
+			var at = reflect.TypeOf(alias).Elem()
+			if ct := def.compiler.Type(); (ct == at || ct.Implements(at)) && !def.hasAlias(at) {
+				def.aliases = append(def.aliases, alias)
+			}
+		}
+
+		if def.compiler.Type() != rt && !def.hasAlias(rt) {
+			continue
+		}
+
+		found = true
+
+		// the single replaced definition keeps being selected by its name and tags
+		if len(replaced) == 1 {
+			if def.name == "" {
+				def.name = replaced[0].name
+			}
+
+			if len(def.tags) == 0 {
+				def.tags = append(Tags(nil), replaced[0].tags...)
+			}
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("%s : replacement %w", frame, NewTypeError(rt, ErrDoesNotExist))
+	}
+
+	var current = b.defs
+	b.defs = b.defs.without(ids)
+	if err = b.insert(defs); err != nil {
+		b.defs = current
+		return err
+	}
+
+	return nil
+}
+
 func (b *builder) Provide(value Constructor, options ...ProvideOption) (err error) {
+	var defs []*definition
+	if defs, err = b.provide(value, runtime.Caller(0), options); err != nil {
+		return err
+	}
+
+	return b.add(defs...)
+}
+
+func (b *builder) Factory(value Constructor, options ...ProvideOption) (err error) {
 	var def = &definition{
 		constraints: constraints{},
 	}
@@ -84,28 +200,140 @@ func (b *builder) Provide(value Constructor, options ...ProvideOption) (err erro
 		def.frame = runtime.Caller(0)
 	}
 
-	if def.compiler, err = compiler.NewConstructor(value); err != nil {
+	var ctor *compiler.Constructor
+	if ctor, err = compiler.NewConstructor(value); err != nil {
+		return fmt.Errorf("%s : %w", def.frame, err)
+	}
+
+	var indexes []int
+	if indexes, err = assisted(ctor.Dependencies(), def.assisted); err != nil {
+		return fmt.Errorf("%s : %w", def.frame, err)
+	}
+
+	if def.compiler, err = compiler.NewFactory(value, indexes); err != nil {
+		return fmt.Errorf("%s : %w", def.frame, err)
+	}
+
+	if err = validateDependencies(def.compiler); err != nil {
 		return fmt.Errorf("%s : %w", def.frame, err)
 	}
 
 	return b.add(def)
 }
 
+func (b *builder) ProvideMethods(value Value, options ...ProvideOption) (err error) {
+	var (
+		frame   = runtime.Caller(0)
+		common  = make([]ProvideOption, 0, len(options))
+		methods = Methods{}
+	)
+
+	for _, o := range options {
+		if m, ok := o.(Methods); ok {
+			for name, opts := range m {
+				methods[name] = append(methods[name], opts...)
+			}
+
+			continue
+		}
+
+		if c, ok := o.(*callerOption); ok {
+			frame = c.frame
+		}
+
+		common = append(common, o)
+	}
+
+	var rv = reflect.ValueOf(value)
+	if !rv.IsValid() {
+		return fmt.Errorf("%s : %w", frame, ErrInvalidValue)
+	}
+
+	var (
+		rt      = rv.Type()
+		unknown []string
+	)
+
+	for name := range methods {
+		if _, ok := rt.MethodByName(name); !ok {
+			unknown = append(unknown, rt.String()+"."+name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%s : method %s %w", frame, strings.Join(unknown, ", "), ErrDoesNotExist)
+	}
+
+	var defs []*definition
+	for i := 0; i < rt.NumMethod(); i++ {
+		var (
+			name     = rt.Method(i).Name
+			fn       = rv.Method(i).Interface()
+			opts, ok = methods[name]
+			provided []*definition
+		)
+
+		if _, err = compiler.NewConstructor(fn); err != nil {
+			if ok {
+				return fmt.Errorf("%s : method %s.%s : %w", frame, rt, name, err)
+			}
+
+			continue
+		}
+
+		if provided, err = b.provide(fn, frame, append(append([]ProvideOption(nil), common...), opts...)); err != nil {
+			return err
+		}
+
+		defs = append(defs, provided...)
+	}
+
+	if len(defs) == 0 {
+		return fmt.Errorf("%s : %w", frame, NewTypeError(rt, ErrNoProviderMethods))
+	}
+
+	return b.add(defs...)
+}
+
 func (b *builder) Build() (Container, error) {
 	b.mux.Lock()
 	defer b.mux.Unlock()
 
+	if b.strict {
+		if err := b.validate(); err != nil {
+			return nil, err
+		}
+	} else if b.warn != nil {
+		for _, err := range b.captives() {
+			b.warn(err)
+		}
+	}
+
 	var defs = definitions{}
 	for k, v := range b.defs {
 		defs[k] = v
 	}
 
+	var core = &containerCore{
+		defs:     defs,
+		size:     len(defs.list()),
+		cache:    make(cache, b.seq+1),
+		unshared: make(map[any][]*closerEntry),
+		order:    b.order,
+		states:   make(map[int]*state),
+		stats:    &counters{},
+		waits:    make(waits),
+		idle:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	core.compile()
+
 	return &container{
-		containerCore: &containerCore{
-			defs:  defs,
-			cache: make(cache),
-		},
-		cycle: cycle.New(),
+		containerCore: core,
+		cycle:         cycle.New(),
+		workers:       (&parallelOption{n: b.parallel}).workers(),
 	}, nil
 }
 
@@ -126,36 +354,297 @@ func (b *builder) Definitions() []Definition {
 	return defs
 }
 
-func (b *builder) add(def *definition) error {
+// provide compiles definitions of the constructor without registering them.
+func (b *builder) provide(value Constructor, frame runtime.Frame, options []ProvideOption) (_ []*definition, err error) {
+	var def = &definition{
+		constraints: constraints{},
+		frame:       frame,
+	}
+
+	def.applyProvideOptions(options...)
+
+	var cmp *compiler.Constructor
+	if cmp, err = compiler.NewConstructor(value); err != nil {
+		return nil, fmt.Errorf("%s : %w", def.frame, err)
+	}
+
+	if err = validateDependencies(cmp); err != nil {
+		return nil, fmt.Errorf("%s : %w", def.frame, err)
+	}
+
+	def.compiler = cmp
+	for _, o := range options {
+		if t, ok := o.(*typedOption); ok {
+			def.compiler = compiler.NewTyped(cmp, t.call)
+		}
+	}
+
+	if len(cmp.Outputs()) > 1 || isOut(cmp.Type()) {
+		return provideOutputs(def, cmp, options)
+	}
+
+	return []*definition{def}, nil
+}
+
+// provideOutputs returns definitions of every output of the constructor. The constructor itself is the root
+// of the outputs, it is not registered and is resolved only through them.
+func provideOutputs(def *definition, cmp *compiler.Constructor, options []ProvideOption) (_ []*definition, err error) {
+	var fields = Fields{}
+	for _, o := range options {
+		if f, ok := o.(Fields); ok {
+			for name, opts := range f {
+				fields[name] = append(fields[name], opts...)
+			}
+		}
+	}
+
+	var root = &definition{
+		compiler:    def.compiler,
+		constraints: def.constraints,
+		frame:       def.frame,
+		priority:    def.priority,
+		unshared:    def.unshared,
+	}
+
+	var output = func(cmp compiler.Compiler) *definition {
+		return &definition{
+			compiler:    cmp,
+			constraints: constraints{},
+			frame:       def.frame,
+			name:        def.name,
+			priority:    def.priority,
+			root:        root,
+			tags:        append(Tags(nil), def.tags...),
+			unshared:    def.unshared,
+		}
+	}
+
+	var (
+		defs  []*definition
+		names []string
+		as    [][]string
+	)
+
+	if rt := cmp.Type(); isOut(rt) {
+		for i := 0; i < rt.NumField(); i++ {
+			var field = rt.Field(i)
+			if !field.IsExported() || field.Anonymous && field.Type == reflectOutType {
+				continue
+			}
+
+			var out *compiler.Output
+			if out, err = compiler.NewOutput(rt, i); err != nil {
+				return nil, fmt.Errorf("%s : %w", def.frame, err)
+			}
+
+			var fd = output(out)
+			fd.tags = append(fd.tags, outTags(field)...)
+			if name, ok := field.Tag.Lookup("name"); ok {
+				fd.name = name
+			}
+
+			defs = append(defs, fd)
+			names = append(names, field.Name)
+			as = append(as, outAliases(field))
+		}
+
+		if len(defs) == 0 {
+			return nil, fmt.Errorf("%s : %w without exported fields", def.frame, NewTypeError(rt, ErrInvalidConstructor))
+		}
+
+		var unknown []string
+		for name := range fields {
+			if !contains(names, name) {
+				unknown = append(unknown, name)
+			}
+		}
+
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return nil, fmt.Errorf("%s : field %s.%s %w", def.frame, rt, unknown[0], ErrDoesNotExist)
+		}
+	} else {
+		for i, typ := range cmp.Outputs() {
+			var out *compiler.Output
+			if out, err = compiler.NewOutputs(typ, i); err != nil {
+				return nil, fmt.Errorf("%s : %w", def.frame, err)
+			}
+
+			defs = append(defs, output(out))
+			names = append(names, "")
+			as = append(as, nil)
+		}
+	}
+
+	if err = distributeAliases(def, defs, as); err != nil {
+		return nil, err
+	}
+
+	for i, fd := range defs {
+		if names[i] == "" {
+			continue
+		}
+
+		fd.applyProvideOptions(fields[names[i]]...)
+		for _, name := range as[i] {
+			if !fd.aliased(name) {
+				return nil, fmt.Errorf(
+					"%s : field %s.%s alias %s %w", def.frame, cmp.Type(), names[i], name, ErrDoesNotExist,
+				)
+			}
+		}
+	}
+
+	return defs, nil
+}
+
+// distributeAliases gives every alias of the constructor to the outputs it fits. The alias named by the as tag
+// of any field goes only to the tagged outputs, otherwise it goes to all outputs implementing it.
+func distributeAliases(def *definition, defs []*definition, as [][]string) error {
+	for _, alias := range def.aliases {
+		var at, err = aliasType(def, alias)
+		if err != nil {
+			return err
+		}
+
+		var targets []*definition
+		for i, fd := range defs {
+			for _, name := range as[i] {
+				if aliasNamed(at, name) {
+					targets = append(targets, fd)
+					break
+				}
+			}
+		}
+
+		if len(targets) == 0 {
+			for _, fd := range defs {
+				if ct := fd.compiler.Type(); ct == at || ct.Implements(at) {
+					targets = append(targets, fd)
+				}
+			}
+		}
+
+		if len(targets) == 0 {
+			return fmt.Errorf("%s : no output %w %s", def.frame, ErrNotImplementInterface, reflect.TypeOf(alias))
+		}
+
+		for _, fd := range targets {
+			fd.aliases = append(fd.aliases, alias)
+		}
+	}
+
+	return nil
+}
+
+// aliasNamed checks that the name of the as tag refers to the alias type.
+func aliasNamed(at reflect.Type, name string) bool {
+	return name == at.String() || name == at.Name()
+}
+
+// contains checks that the list contains the value.
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add registers the definitions, nothing is registered if any of them is invalid.
+func (b *builder) add(defs ...*definition) error {
 	b.mux.Lock()
 	defer b.mux.Unlock()
 
-	b.seq++
-	def.id = b.seq
+	return b.insert(defs)
+}
 
-	var ct = def.compiler.Type()
-	b.defs[ct] = append(b.defs[ct], *def)
+// insert checks all definitions first and then registers them, must be called under the lock.
+func (b *builder) insert(defs []*definition) error {
+	var (
+		types   = make([][]reflect.Type, len(defs))
+		pending = make(map[reflect.Type][]*definition)
+	)
 
-	for _, alias := range def.aliases {
-		if alias == nil {
-			return fmt.Errorf("%s : %w", def.frame, ErrIsNil)
+	for i, def := range defs {
+		var ct = def.compiler.Type()
+		types[i] = append(types[i], ct)
+
+		for _, alias := range def.aliases {
+			var at, err = aliasType(def, alias)
+			if err != nil {
+				return err
+			}
+
+			if ct == at {
+				continue
+			}
+
+			if !ct.Implements(at) {
+				return fmt.Errorf("%s : %s %w %s", def.frame, ct.String(), ErrNotImplementInterface, reflect.TypeOf(alias))
+			}
+
+			types[i] = append(types[i], at)
 		}
 
-		var at = reflect.TypeOf(alias)
-		if at.Kind() != reflect.Ptr || at.Elem().Kind() != reflect.Interface {
-			return fmt.Errorf("%s : %w", def.frame, NewTypeError(at, ErrNotPointerToInterface))
+		for _, typ := range types[i] {
+			if err := b.unique(typ, def, pending[typ]); err != nil {
+				return err
+			}
+
+			pending[typ] = append(pending[typ], def)
 		}
+	}
 
-		if ct == at.Elem() {
-			continue
+	for i, def := range defs {
+		if def.root != nil && def.root.id == 0 {
+			b.seq++
+			def.root.id = b.seq
 		}
 
-		if !ct.Implements(at.Elem()) {
-			return fmt.Errorf("%s : %s %w %s", def.frame, ct.String(), ErrNotImplementInterface, at.String())
+		b.seq++
+		def.id = b.seq
+
+		for _, typ := range types[i] {
+			b.defs[typ] = append(b.defs[typ], *def)
 		}
+	}
+
+	return nil
+}
 
-		b.defs[at.Elem()] = append(b.defs[at.Elem()], *def)
+func (b *builder) unique(typ reflect.Type, def *definition, pending []*definition) error {
+	if def.name == "" {
+		return nil
+	}
+
+	for i := range b.defs[typ] {
+		if b.defs[typ][i].name == def.name {
+			return fmt.Errorf("%s : %s %w %q, already provided at %s", def.frame, typ, ErrDuplicateName, def.name, b.defs[typ][i].frame)
+		}
+	}
+
+	for _, other := range pending {
+		if other.name == def.name {
+			return fmt.Errorf("%s : %s %w %q, already provided at %s", def.frame, typ, ErrDuplicateName, def.name, other.frame)
+		}
 	}
 
 	return nil
 }
+
+// aliasType returns the interface type of the alias, the alias must be a pointer to an interface.
+func aliasType(def *definition, alias any) (reflect.Type, error) {
+	if alias == nil {
+		return nil, fmt.Errorf("%s : %w", def.frame, ErrIsNil)
+	}
+
+	var at = reflect.TypeOf(alias)
+	if at.Kind() != reflect.Ptr || at.Elem().Kind() != reflect.Interface {
+		return nil, fmt.Errorf("%s : %w", def.frame, NewTypeError(at, ErrNotPointerToInterface))
+	}
+
+	return at.Elem(), nil
+}
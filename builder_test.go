@@ -134,6 +134,91 @@ func TestBuilder(t *testing.T) {
 			require.ErrorIs(t, err, di.ErrInvalidConstructor)
 			require.ErrorContains(t, err, "builder_test.go:130")
 		},
+	}, {
+		Name: "Builder -> ProvideMethods",
+		Runner: func(t *testing.T, builder di.Builder) {
+			var err = builder.ProvideMethods(&Providers{}, di.Methods{
+				"NewServer": {di.Tags{{Name: "server"}}},
+			})
+			require.NoError(t, err)
+			require.Len(t, builder.Definitions(), 2)
+
+			for _, def := range builder.Definitions() {
+				require.Equal(t, def.Type().String() == "*http.Server", len(def.Tags()) == 1)
+			}
+		},
+	}, {
+		Name: "Builder -> ProvideMethods with invalid method",
+		Runner: func(t *testing.T, builder di.Builder) {
+			var err = builder.ProvideMethods(&Providers{}, di.Methods{
+				"Register": {di.Tags{{Name: "server"}}},
+			})
+			require.Error(t, err)
+			require.ErrorIs(t, err, di.ErrInvalidConstructor)
+			require.ErrorContains(t, err, "builder_test.go:153")
+		},
+	}, {
+		Name: "Builder -> Apply -> ProvideMethods without methods",
+		Runner: func(t *testing.T, builder di.Builder) {
+			var err = builder.Apply(
+				di.ProvideMethods(Providers{}),
+			)
+			require.Error(t, err)
+			require.ErrorIs(t, err, di.ErrNoProviderMethods)
+			require.ErrorContains(t, err, "builder_test.go:164")
+		},
+	}, {
+		Name: "Builder -> Provide with duplicate name",
+		Runner: func(t *testing.T, builder di.Builder) {
+			var err = builder.Apply(
+				di.Provide(NewBarController, di.Name("primary"), di.As(new(Controller))),
+				di.Provide(NewBarController, di.Name("primary-replica")),
+				di.Provide(NewBazController, di.Name("replica")),
+			)
+			require.NoError(t, err)
+
+			err = builder.Provide(NewBazController, di.Name("primary"), di.As(new(Controller)))
+			require.Error(t, err)
+			require.ErrorIs(t, err, di.ErrDuplicateName)
+			require.ErrorContains(t, err, "builder_test.go:180")
+		},
+	}, {
+		Name: "Builder -> Override",
+		Runner: func(t *testing.T, builder di.Builder) {
+			var err = builder.Apply(
+				di.Provide(NewBarController, di.As(new(Controller))),
+				di.Provide(NewBazController, di.As(new(Controller))),
+				di.Replace(new(Controller), NewCycledController),
+				di.Replace((*CycledController)(nil), NewCycledController, di.As(new(Controller)), di.Unshared()),
+			)
+			require.NoError(t, err)
+
+			var defs = builder.Definitions()
+			require.Len(t, defs, 2)
+			require.Equal(t, defs[0].ID(), defs[1].ID())
+			require.Equal(t, "*di_test.CycledController", defs[0].Type().String())
+			require.True(t, defs[0].Unshared())
+		},
+	}, {
+		Name: "Builder -> Override with error",
+		Runner: func(t *testing.T, builder di.Builder) {
+			var err = builder.Override(new(Controller), NewBarController)
+			require.Error(t, err)
+			require.ErrorIs(t, err, di.ErrDoesNotExist)
+			require.ErrorContains(t, err, "builder_test.go:205")
+
+			err = builder.Apply(
+				di.Provide(NewBarController, di.Tags{{Name: "bar"}}),
+				di.Replace((*BarController)(nil), NewBazController),
+			)
+			require.Error(t, err)
+			require.ErrorIs(t, err, di.ErrDoesNotExist)
+			require.ErrorContains(t, err, "builder_test.go:212")
+
+			var defs = builder.Definitions()
+			require.Len(t, defs, 1)
+			require.Equal(t, "bar", defs[0].Tags()[0].Name)
+		},
 	}}
 
 	for _, tc := range testCases {
@@ -150,3 +235,274 @@ func TestBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_Strict(t *testing.T) {
+	type testCase struct {
+		Name    string
+		Options []di.BuilderOption
+		Errors  []error
+	}
+
+	var testCases = []testCase{{
+		Name: "Valid",
+		Options: []di.BuilderOption{
+			di.Provide(NewBarController, di.As(new(Controller))),
+			di.Provide(NewBazController, di.As(new(Controller))),
+			di.Provide(NewServerMux),
+			di.Provide(NewServer, di.Constraint(0, di.Optional(true))),
+		},
+	}, {
+		Name: "Duplicate",
+		Options: []di.BuilderOption{
+			di.Provide(NewBarController, di.Tags{{Name: "bar"}}),
+			di.Provide(NewBarController, di.Tags{{Name: "bar"}}),
+			di.Provide(NewBarController, di.Tags{{Name: "baz"}}),
+		},
+		Errors: []error{di.ErrMultipleDefinitions},
+	}, {
+		Name: "Container injection",
+		Options: []di.BuilderOption{
+			di.Provide(NewBarController),
+			di.Provide(NewManualResolver),
+		},
+		Errors: []error{di.ErrContainerInjection},
+	}, {
+		Name: "Unknown constraint key",
+		Options: []di.BuilderOption{
+			di.Provide(NewBarController, di.Constraint(0, di.Optional(true))),
+		},
+		Errors: []error{di.ErrDoesNotExist},
+	}, {
+		Name: "Unsatisfiable optional dependency",
+		Options: []di.BuilderOption{
+			di.Provide(NewServer, di.Constraint(0, di.Optional(true))),
+		},
+		Errors: []error{di.ErrDoesNotExist},
+	}, {
+		Name: "Unshared closer",
+		Options: []di.BuilderOption{
+			di.Provide(NewServerMux, di.Unshared()),
+		},
+		Errors: []error{di.ErrUnsharedCloser},
+	}, {
+		Name: "Several violations",
+		Options: []di.BuilderOption{
+			di.Provide(NewBarController),
+			di.Provide(NewManualResolver),
+			di.Provide(NewServerMux, di.Unshared()),
+		},
+		Errors: []error{di.ErrContainerInjection, di.ErrUnsharedCloser},
+	}, {
+		Name: "Empty group",
+		Options: []di.BuilderOption{
+			di.Provide(func(p struct {
+				di.In
+
+				Controllers []Controller `group:"controller"`
+			}) *BarController {
+				return &BarController{}
+			}),
+		},
+	}, {
+		Name: "Captive dependency",
+		Options: []di.BuilderOption{
+			di.Provide(NewBarController, di.As(new(Controller)), di.Unshared()),
+			di.Provide(NewServerMux),
+		},
+		Errors: []error{di.ErrCaptiveDependency},
+	}, {
+		Name: "Indirect captive dependency",
+		Options: []di.BuilderOption{
+			di.Provide(NewBarController, di.As(new(Controller)), di.Unshared()),
+			di.Provide(NewServerMux),
+			di.Provide(NewServer),
+		},
+		Errors: []error{di.ErrCaptiveDependency, di.ErrCaptiveDependency},
+	}, {
+		Name: "Provider of unshared dependency",
+		Options: []di.BuilderOption{
+			di.Provide(NewBarController, di.Unshared()),
+			di.Provide(func(func() (*BarController, error)) *BazController { return &BazController{} }),
+		},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var builder, err = di.NewBuilder(di.Strict())
+			require.NoError(t, err)
+
+			err = builder.Apply(tc.Options...)
+			require.NoError(t, err)
+
+			_, err = builder.Build()
+			if len(tc.Errors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var verr *di.ValidationError
+			require.ErrorAs(t, err, &verr)
+			require.Len(t, verr.Errors, len(tc.Errors))
+
+			for i, expected := range tc.Errors {
+				require.ErrorIs(t, verr.Errors[i], expected)
+				require.ErrorContains(t, verr.Errors[i], "builder_test.go:")
+			}
+		})
+	}
+}
+
+func TestBuilder_Warnings(t *testing.T) {
+	var warnings []error
+	var builder, err = di.NewBuilder(
+		di.Warnings(func(err error) {
+			warnings = append(warnings, err)
+		}),
+		di.Provide(NewBarController, di.As(new(Controller)), di.Unshared()),
+		di.Provide(NewServerMux),
+		di.Provide(NewServer, di.Unshared()),
+	)
+
+	require.NoError(t, err)
+
+	_, err = builder.Build()
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.ErrorIs(t, warnings[0], di.ErrCaptiveDependency)
+	require.ErrorContains(t, warnings[0], "type *http.ServeMux : captive dependency on unshared *di_test.BarController")
+	require.Regexp(t, `builder_test.go:\d+ : .* provided at .*builder_test.go:\d+$`, warnings[0].Error())
+
+	warnings = nil
+	builder, err = di.NewBuilder(
+		di.Warnings(func(err error) {
+			warnings = append(warnings, err)
+		}),
+		di.Provide(NewBarController, di.As(new(Controller)), di.Unshared()),
+		di.Provide(NewServerMux),
+		di.Provide(NewServer),
+	)
+
+	require.NoError(t, err)
+
+	_, err = builder.Build()
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+	require.ErrorContains(t, warnings[1], "type *http.Server : captive dependency on unshared *di_test.BarController")
+	require.Regexp(t, `through \*http.ServeMux provided at .*builder_test.go:\d+$`, warnings[1].Error())
+}
+
+func TestBuilder_AddAtomic(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.Name("bar")),
+	)
+
+	require.NoError(t, err)
+
+	err = builder.Provide(NewManualResolver, di.As(new(Controller)))
+	require.ErrorIs(t, err, di.ErrNotImplementInterface)
+
+	err = builder.Provide(NewBazController, di.As(new(Controller), nil))
+	require.ErrorIs(t, err, di.ErrIsNil)
+
+	err = builder.Provide(NewBarController, di.Name("bar"), di.As(new(Controller)))
+	require.ErrorIs(t, err, di.ErrDuplicateName)
+
+	require.Len(t, builder.Definitions(), 1)
+}
+
+func TestBuilder_ProvideMethods(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(NewServerMux, di.Name("mux")),
+	)
+
+	require.NoError(t, err)
+
+	err = builder.ProvideMethods(&Providers{}, di.Methods{
+		"Zed":   {di.Name("zed")},
+		"Alpha": {di.Name("alpha")},
+	})
+
+	require.ErrorIs(t, err, di.ErrDoesNotExist)
+	require.ErrorContains(t, err, "method *di_test.Providers.Alpha, *di_test.Providers.Zed does not exist")
+
+	err = builder.ProvideMethods(&Providers{}, di.Methods{
+		"NewServerMux": {di.Name("mux")},
+	})
+
+	require.ErrorIs(t, err, di.ErrDuplicateName)
+	require.Len(t, builder.Definitions(), 1)
+}
+
+func TestBuilder_Override(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Controller)), di.Name("bar")),
+		di.Provide(NewBazController, di.As(new(Controller)), di.Name("baz")),
+		di.Replace((*BarController)(nil), func() *BarController { return &BarController{} }),
+		di.Replace(new(Controller), NewCycledController, di.Replacing(di.Named("baz"))),
+	)
+
+	require.NoError(t, err)
+
+	var types = make(map[string]int)
+	for _, def := range builder.Definitions() {
+		types[def.Type().String()]++
+	}
+
+	require.Equal(t, map[string]int{
+		"*di_test.BarController":    2,
+		"*di_test.CycledController": 2,
+	}, types)
+
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, builder.Override((*BarController)(nil), NewBarController))
+		}
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+
+		var found = false
+		for _, def := range builder.Definitions() {
+			found = found || def.Type().String() == "*di_test.BarController"
+		}
+
+		require.True(t, found)
+	}
+}
+
+func TestBuilder_OverrideNameTags(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Controller)), di.Name("bar"), di.Tags{{Name: "controller"}}),
+		di.Provide(func(bar *BarController, controllers []Controller) *BazController {
+			require.Len(t, controllers, 1)
+			return &BazController{}
+		}, di.Constraint(0, di.Named("bar")), di.Constraint(1, di.WithTags("controller"))),
+		di.Replace((*BarController)(nil), func() *BarController { return &BarController{} }),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var baz *BazController
+	require.NoError(t, ctn.Resolve(&baz))
+
+	err = builder.Override((*BarController)(nil), NewBarController, di.Name("fake"))
+	require.NoError(t, err)
+
+	for _, def := range builder.Definitions() {
+		if def.Type().String() == "*di_test.BarController" {
+			require.Equal(t, "fake", def.Name())
+			require.Equal(t, di.Tags{{Name: "controller"}}, def.Tags())
+		}
+	}
+}
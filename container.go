@@ -8,7 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gozix/di/internal/compiler"
 	"github.com/gozix/di/internal/cycle"
@@ -21,28 +24,116 @@ type (
 		*containerCore
 		*resolver
 
-		cycle *cycle.Cycle
+		cycle   *cycle.Cycle
+		def     *definition
+		overlay *overlay
+		workers chan struct{}
+
+		// built is set when the construction of def has finished, Lazy and Provider created for the construction
+		// resolve outside of it afterwards, only recording dependencies of def as dependent
+		built     int32
+		dependent *definition
+
+		// owned are unshared instances created for the unshared definition or the Call with AutoRelease
+		owned  []*closerEntry
+		owning bool
 	}
 
 	// container core values
 	containerCore struct {
-		mux     sync.Mutex
-		defs    definitions
-		cache   cache
-		closers []compiler.Closer
+		// active is number of in-flight resolutions with closedBit, must be the first field to be 64-bit aligned
+		active   int64
+		mux      sync.Mutex
+		defs     definitions
+		size     int
+		cache    cache
+		closers  []*closerEntry
+		unshared map[any][]*closerEntry
+		order    Modifier
+		states   map[int]*state
+		stats    *counters
+		waits    waits
+		plans    map[int][]plan
+		index    map[int]*definition
+		choices  sync.Map
+		idle     chan struct{}
+		done     chan struct{}
+		err      error
+	}
+
+	// container statistics counters, updated atomically
+	counters struct {
+		instances         uint64
+		cacheHits         uint64
+		cacheMisses       uint64
+		failures          uint64
+		closersRegistered uint64
+		closersRun        uint64
+		constructionTime  int64
 	}
 
 	// container dependency resolver
 	resolver struct{}
 
-	// container cache
-	cache map[int]*cacheItem
+	// closer of the definition instance, the unshared instance also keeps its value and the entries
+	// of unshared instances created for it, the instance created by Refresh keeps its overlay until merge
+	closerEntry struct {
+		id      int
+		closer  compiler.Closer
+		key     any
+		owned   []*closerEntry
+		overlay *overlay
+	}
+
+	// container cache of shared instances indexed by definition id
+	cache []cacheSlot
+
+	// container cache slot, the item is published atomically, so cache hits take no locks
+	cacheSlot struct {
+		item atomic.Value
+	}
+
+	// choice describes how the container resolves the type.
+	choice struct {
+		typ   reflect.Type
+		elem  reflect.Type
+		kind  choiceKind
+		defs  []definition
+		keyed bool
+	}
+
+	// choiceKind is kind of the choice.
+	choiceKind int
+
+	// waits are edges between definitions waiting one for another, with the number of the waits
+	waits map[int]map[int]int
 
 	// container cache item
 	cacheItem struct {
 		value reflect.Value
+		err   error
 		ready chan struct{}
 	}
+
+	// definition runtime state
+	state struct {
+		instances  int
+		createdAt  time.Time
+		duration   time.Duration
+		closer     bool
+		dependents map[int]bool
+	}
+)
+
+// closedBit marks the closed container in containerCore.active.
+const closedBit = int64(1) << 62
+
+const (
+	choiceDefinitions choiceKind = iota
+	choiceContainer
+	choiceTags
+	choiceProvider
+	choiceLazy
 )
 
 var (
@@ -54,6 +145,9 @@ var (
 
 	// reflectContainerType is Container reflect type cache.
 	reflectContainerType = reflect.TypeOf((*Container)(nil)).Elem()
+
+	// reflectTagsType is Tags reflect type cache.
+	reflectTagsType = reflect.TypeOf(Tags(nil))
 )
 
 func (c *container) Call(fn Function, options ...ConstraintOption) (err error) {
@@ -62,40 +156,86 @@ func (c *container) Call(fn Function, options ...ConstraintOption) (err error) {
 		return fmt.Errorf("%s : fn %w", runtime.Caller(0), ErrorMustBeFunction)
 	}
 
+	if !c.begin() {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), ErrClosed)
+	}
+
 	var (
-		rt = rv.Type()
-		in = make([]reflect.Value, 0, rt.NumIn())
-		cs = make(constraints, len(options))
+		rt       = rv.Type()
+		in       = make([]reflect.Value, 0, rt.NumIn())
+		cs       = make(constraints, len(options))
+		parallel *parallelOption
 	)
 
 	for _, o := range options {
+		if p, ok := o.(*parallelOption); ok {
+			parallel = p
+		}
+
 		o.applyConstraintOption(cs)
 	}
 
-	var (
-		vt = rt.IsVariadic()
-		ic = rt.NumIn() - 1
-	)
+	var ctn = c
+	if parallel != nil {
+		if parallel.n < 0 {
+			c.end()
+			return fmt.Errorf("%s : parallel %d %w", parallel.frame, parallel.n, ErrInvalidValue)
+		}
 
-	for i := 0; i <= ic; i++ {
-		var (
-			at  = rt.In(i)
-			dep = &compiler.Dependency{
-				Name:  at.Name(),
-				Index: i,
-				Type:  at,
-				Value: reflect.New(at),
+		ctn = &container{
+			containerCore: c.containerCore,
+			cycle:         c.cycle,
+			workers:       parallel.workers(),
+		}
+	}
+
+	if _, ok := cs[autoReleaseOption{}]; ok {
+		ctn = &container{
+			containerCore: c.containerCore,
+			cycle:         c.cycle,
+			workers:       ctn.workers,
+			owning:        true,
+		}
+
+		defer func() {
+			// the container closed meanwhile has already run the closers
+			if !c.begin() {
+				return
 			}
-		)
 
-		err = c.resolveDependency(c, dep, cs)
+			defer c.end()
 
-		if err != nil {
-			return fmt.Errorf("%s : %w", runtime.Caller(0), err)
-		}
+			c.mux.Lock()
+			var closers = c.releaseEntries(ctn.owned...)
+			c.mux.Unlock()
 
-		if vt && i == ic {
-			for i = 0; i < dep.Value.Elem().Len(); i++ {
+			if closeErr := c.close(closers); closeErr != nil && err == nil {
+				err = fmt.Errorf("%s : %w", runtime.Caller(0), closeErr)
+			}
+		}()
+	}
+
+	var deps = make([]*compiler.Dependency, 0, rt.NumIn())
+	for i := 0; i < rt.NumIn(); i++ {
+		var at = rt.In(i)
+		deps = append(deps, &compiler.Dependency{
+			Name:  at.Name(),
+			Index: i,
+			Type:  at,
+			Value: reflect.New(at),
+		})
+	}
+
+	err = c.resolveDependencies(ctn, deps, cs, nil)
+	c.end()
+
+	if err != nil {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), err)
+	}
+
+	for _, dep := range deps {
+		if rt.IsVariadic() && dep.Index == rt.NumIn()-1 {
+			for i := 0; i < dep.Value.Elem().Len(); i++ {
 				in = append(in, dep.Value.Elem().Index(i))
 			}
 
@@ -114,36 +254,194 @@ func (c *container) Call(fn Function, options ...ConstraintOption) (err error) {
 }
 
 func (c *container) Close() (err error) {
-	c.mux.Lock()
+	if !c.shutdown() {
+		<-c.done
+		return c.err
+	}
 
-	var closers = append(([]compiler.Closer)(nil), c.closers...)
-	c.defs = make(definitions, 0)
-	c.closers = c.closers[:0]
+	<-c.idle
 
+	c.mux.Lock()
+	var closers = c.closers
+	c.closers = nil
+	c.unshared = nil
 	c.mux.Unlock()
 
+	defer close(c.done)
+
 	for i := len(closers) - 1; i >= 0; i-- {
-		if err = closers[i](); err != nil {
-			return fmt.Errorf("unable to close container : %w", err)
+		atomic.AddUint64(&c.stats.closersRun, 1)
+		if err = closers[i].closer(); err != nil {
+			c.err = fmt.Errorf("unable to close container : %w", err)
+			return c.err
 		}
 	}
 
 	return nil
 }
 
+func (c *container) Count(value Type, modifiers ...Modifier) int {
+	var n, _ = c.count(value, modifiers)
+	return n
+}
+
+func (c *container) Definitions() []Definition {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var (
+		list = c.defs.list()
+		defs = make([]Definition, 0, len(list))
+	)
+
+	for i := range list {
+		defs = append(defs, &list[i])
+	}
+
+	return defs
+}
+
+func (c *container) Explain(value Type, modifiers ...Modifier) string {
+	if !c.begin() {
+		return fmt.Sprintf("type %v : %s", value, ErrClosed)
+	}
+
+	defer c.end()
+
+	var ch, err = c.target(value, modifiers)
+	if ch.typ == nil {
+		return fmt.Sprintf("type %v : %s", value, err)
+	}
+
+	var sb strings.Builder
+	switch ch.kind {
+	case choiceContainer:
+		fmt.Fprintf(&sb, "type %s is resolved by the container itself", ch.typ)
+	case choiceTags:
+		fmt.Fprintf(&sb, "type %s is resolved by tags of the dependent definition", ch.typ)
+	case choiceProvider:
+		fmt.Fprintf(&sb, "type %s is resolved by provider function of %s", ch.typ, ch.elem)
+	case choiceLazy:
+		fmt.Fprintf(&sb, "type %s is resolved lazily by %s", ch.typ, ch.elem)
+	default:
+		fmt.Fprintf(&sb, "type %s is resolved", ch.typ)
+	}
+
+	if ch.kind != choiceContainer && ch.kind != choiceTags {
+		switch {
+		case ch.keyed:
+			fmt.Fprintf(&sb, ", map of %s definitions by key", ch.elem.Elem())
+		case ch.elem.Kind() == reflect.Slice && len(ch.defs) > 0 && ch.defs[0].Type() != ch.elem:
+			fmt.Fprintf(&sb, ", slice of %s definitions", ch.elem.Elem())
+		case ch.elem.Kind() == reflect.Slice:
+			fmt.Fprintf(&sb, ", all %s definitions", ch.elem)
+		}
+
+		fmt.Fprintf(&sb, " with %d modifier(s), %d definition(s) chosen", len(modifiers), len(ch.defs))
+
+		for _, def := range ch.defs {
+			fmt.Fprintf(&sb, "\n\t#%d %s", def.id, def.Type())
+			if def.key != "" {
+				fmt.Fprintf(&sb, " key %q", def.key)
+			}
+
+			if def.name != "" {
+				fmt.Fprintf(&sb, " name %q", def.name)
+			}
+
+			if len(def.tags) > 0 {
+				var names = make([]string, 0, len(def.tags))
+				for _, tag := range def.tags {
+					names = append(names, tag.Name)
+				}
+
+				fmt.Fprintf(&sb, " tags [%s]", strings.Join(names, ", "))
+			}
+
+			if def.priority != 0 {
+				fmt.Fprintf(&sb, " priority %d", def.priority)
+			}
+
+			if def.unshared {
+				sb.WriteString(" unshared")
+			}
+
+			fmt.Fprintf(&sb, " provided at %s", def.frame)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(&sb, "\n\terror : %s", err)
+	}
+
+	return sb.String()
+}
+
 func (c *container) Has(value Type, modifiers ...Modifier) bool {
+	var n, err = c.count(value, modifiers)
+	return err == nil && n > 0
+}
+
+func (c *container) Stats() Stats {
+	return Stats{
+		Definitions:       c.size,
+		Instances:         atomic.LoadUint64(&c.stats.instances),
+		CacheHits:         atomic.LoadUint64(&c.stats.cacheHits),
+		CacheMisses:       atomic.LoadUint64(&c.stats.cacheMisses),
+		Failures:          atomic.LoadUint64(&c.stats.failures),
+		ClosersRegistered: atomic.LoadUint64(&c.stats.closersRegistered),
+		ClosersRun:        atomic.LoadUint64(&c.stats.closersRun),
+		ConstructionTime:  time.Duration(atomic.LoadInt64(&c.stats.constructionTime)),
+	}
+}
+
+func (c *container) State(def Definition) State {
 	c.mux.Lock()
 	defer c.mux.Unlock()
 
+	var st = c.states[def.ID()]
+	if st == nil {
+		return State{}
+	}
+
 	var (
-		rt   = reflect.TypeOf(value)
-		defs = c.defs.find(rt, modifiers)
+		defs = c.defs.list()
+		deps = make([]Definition, 0, len(st.dependents))
 	)
 
-	return len(defs) > 0
+	for i := range defs {
+		if st.dependents[defs[i].id] {
+			deps = append(deps, &defs[i])
+		}
+	}
+
+	// the output is taken from the instance of its constructor, which is created and closed instead of it
+	var created = st
+	if d := c.index[def.ID()]; d != nil && d.root != nil && c.states[d.root.id] != nil {
+		created = c.states[d.root.id]
+	}
+
+	return State{
+		Instantiated: st.instances > 0,
+		Instances:    st.instances,
+		CreatedAt:    created.createdAt,
+		Duration:     created.duration,
+		Closer:       created.closer,
+		Dependents:   deps,
+	}
+}
+
+func (c *container) Done() <-chan struct{} {
+	return c.done
 }
 
 func (c *container) Resolve(target Value, modifiers ...Modifier) (err error) {
+	if !c.begin() {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), ErrClosed)
+	}
+
+	defer c.end()
+
 	var rv = reflect.ValueOf(target)
 	if err = c.resolve(c, &rv, modifiers); err != nil {
 		return fmt.Errorf("%s : %w", runtime.Caller(0), err)
@@ -153,11 +451,7 @@ func (c *container) Resolve(target Value, modifiers ...Modifier) (err error) {
 }
 
 func (r *resolver) resolve(ctn *container, tv *reflect.Value, modifiers []Modifier) (err error) {
-	defer func() {
-		if recovered := recover(); recovered != nil {
-			err = fmt.Errorf("unable to resolve target because the container panicked: %+v", recovered)
-		}
-	}()
+	defer r.recover(&err)
 
 	if tv.Kind() != reflect.Pointer && tv.Kind() != reflect.Slice {
 		if tv.IsValid() {
@@ -167,102 +461,448 @@ func (r *resolver) resolve(ctn *container, tv *reflect.Value, modifiers []Modifi
 		return ErrMustBeSliceOrPointer
 	}
 
+	var p = r.chosen(ctn, tv.Type().Elem(), modifiers)
+	if p.err != nil {
+		return NewTypeError(tv.Type(), p.err)
+	}
+
+	return r.apply(ctn, tv, &p.choice, modifiers)
+}
+
+// chosen returns the choice of the type, the choices of types resolved without modifiers outside
+// of constructions do not depend on the context and are cached.
+func (r *resolver) chosen(ctn *container, ft reflect.Type, modifiers []Modifier) *plan {
+	if len(modifiers) > 0 || ctn.def != nil {
+		var ch, err = r.choose(ctn, ft, modifiers)
+		return &plan{choice: ch, err: err}
+	}
+
+	if p, ok := ctn.choices.Load(ft); ok {
+		return p.(*plan)
+	}
+
+	var ch, err = r.choose(ctn, ft, nil)
+	var p, _ = ctn.choices.LoadOrStore(ft, &plan{choice: ch, err: err})
+
+	return p.(*plan)
+}
+
+// apply fills the target by the choice.
+func (r *resolver) apply(ctn *container, tv *reflect.Value, ch *choice, modifiers []Modifier) (err error) {
 	var ft = tv.Type().Elem()
-	if reflectContainerType.AssignableTo(ft) {
+	switch ch.kind {
+	case choiceContainer:
 		r.set(tv, reflect.ValueOf(ctn))
+		return nil
+	case choiceTags:
+		r.set(tv, reflect.ValueOf(ctn.def.Tags()))
+		return nil
+	case choiceProvider:
+		r.set(tv, r.provider(ctn, ft, ch.elem, modifiers))
+		return nil
+	case choiceLazy:
+		r.set(tv, r.lazy(ctn, ft, ch.elem, modifiers))
+		return nil
+	}
+
+	var (
+		defs  = ch.defs
+		keyed = ch.keyed
+	)
+
+	if keyed && tv.Elem().IsNil() {
+		tv.Elem().Set(reflect.MakeMapWithSize(ft, len(defs)))
+	}
+
+	for i := range defs {
+		var (
+			def = &defs[i]
+			sv  reflect.Value
+		)
+
+		if sv, err = r.instance(ctn, def, tv.Type()); err != nil {
+			return err
+		}
+
+		r.depend(ctn, def)
+		r.setKey(tv, def.key, keyed, sv)
+	}
+
+	return nil
+}
+
+// instance returns the cached instance of the definition or creates a new one.
+func (r *resolver) instance(ctn *container, def *definition, rt reflect.Type) (_ reflect.Value, err error) {
+	var (
+		item  *cacheItem
+		owner bool
+	)
+
+	if !def.unshared {
+		item, owner = ctn.slot(def.id).acquire()
+		if owner {
+			atomic.AddUint64(&ctn.stats.cacheMisses, 1)
+		} else {
+			atomic.AddUint64(&ctn.stats.cacheHits, 1)
+		}
+	}
+
+	if item != nil && !owner && item.done() {
+		return item.value, item.err
+	}
+
+	if ctn.cycle.Has(def.id) {
+		var path = ctn.cycle.Path()
+		for path[0] != def.id {
+			path = path[1:]
+		}
+
+		return reflect.Value{}, NewTypeError(rt, ctn.cycleError(append(path, def.id)))
+	}
+
+	if err = r.enter(ctn, def); err != nil {
+		return reflect.Value{}, NewTypeError(rt, err)
+	}
+
+	defer r.leave(ctn, def)
+
+	if item != nil && !owner {
+		<-item.ready
+		return item.value, item.err
+	}
+
+	var sv reflect.Value
+	sv, err = r.create(ctn, def)
+
+	if owner {
+		item.value, item.err = sv, err
+		close(item.ready)
+	}
+
+	return sv, err
+}
+
+// enter records that the definition of the container waits for the def. The wait closing a cycle, even
+// across goroutines, is rejected with ErrCycleDetected, because it would never end.
+func (r *resolver) enter(ctn *container, def *definition) error {
+	if ctn.def == nil {
+		return nil
+	}
+
+	ctn.mux.Lock()
+	defer ctn.mux.Unlock()
+
+	if path := ctn.waits.path(def.id, ctn.def.id); path != nil {
+		return ctn.cycleError(append(path, def.id))
+	}
+
+	ctn.waits.add(ctn.def.id, def.id)
+
+	return nil
+}
+
+// leave removes the wait recorded by enter.
+func (r *resolver) leave(ctn *container, def *definition) {
+	if ctn.def == nil {
 		return
 	}
 
 	ctn.mux.Lock()
-	var defs = ctn.defs.find(ft, modifiers)
-	ctn.mux.Unlock()
+	defer ctn.mux.Unlock()
+
+	ctn.waits.remove(ctn.def.id, def.id)
+}
 
-	if len(defs) == 0 {
-		if ft.Kind() == reflect.Slice {
-			ctn.mux.Lock()
-			defs = ctn.defs.find(ft.Elem(), modifiers)
-			ctn.mux.Unlock()
+func (r *resolver) create(ctn *container, def *definition) (_ reflect.Value, err error) {
+	var (
+		deps   = def.compiler.Dependencies()
+		newCtn = &container{
+			containerCore: ctn.containerCore,
+			cycle:         ctn.cycle.Append(def.id),
+			def:           def,
+			overlay:       ctn.overlay,
+			workers:       ctn.workers,
+			owning:        def.unshared,
 		}
+	)
+
+	defer atomic.StoreInt32(&newCtn.built, 1)
+
+	if err = r.resolveDependencies(newCtn, deps, def.constraints, ctn.plans[def.id]); err != nil {
+		return reflect.Value{}, err
+	}
+
+	var (
+		sv     reflect.Value
+		closer compiler.Closer
+		start  = time.Now()
+	)
+
+	if sv, closer, err = def.compiler.Create(deps...); err != nil {
+		atomic.AddUint64(&ctn.stats.failures, 1)
+		return reflect.Value{}, NewTypeError(def.compiler.Type(), err)
+	}
+
+	var duration = time.Since(start)
+	atomic.AddUint64(&ctn.stats.instances, 1)
+	atomic.AddInt64(&ctn.stats.constructionTime, int64(duration))
+
+	ctn.mux.Lock()
+	defer ctn.mux.Unlock()
+
+	var st = ctn.state(def.id)
+	st.instances++
+	st.createdAt = start
+	st.duration = duration
+
+	var entry = &closerEntry{id: def.id, closer: closer, owned: newCtn.owned, overlay: ctn.overlay}
+	newCtn.owning = false
+
+	if closer != nil {
+		atomic.AddUint64(&ctn.stats.closersRegistered, 1)
+		st.closer = true
+		ctn.closers = append(ctn.closers, entry)
+	}
+
+	if def.unshared && (closer != nil || len(entry.owned) > 0) {
+		ctn.own(entry, sv)
+	}
+
+	return sv, nil
+}
+
+// count returns the number of chosen definitions, conflicting definitions are counted with the error.
+func (c *container) count(value Type, modifiers []Modifier) (int, error) {
+	if !c.begin() {
+		return 0, ErrClosed
+	}
+
+	defer c.end()
+
+	var ch, err = c.target(value, modifiers)
+	if err != nil && !errors.Is(err, ErrMultipleDefinitions) {
+		return 0, err
+	}
+
+	switch ch.kind {
+	case choiceContainer, choiceTags:
+		return 1, err
+	default:
+		return len(ch.defs), err
+	}
+}
+
+// target chooses definitions of the type value the same way as Resolve does, the pointer type falls back
+// to its element type if the pointer type itself can not be resolved.
+func (c *container) target(value Type, modifiers []Modifier) (ch choice, err error) {
+	var rt = reflect.TypeOf(value)
+	if rt == nil {
+		return ch, ErrInvalidType
+	}
+
+	ch, err = c.choose(c, rt, modifiers)
+	if errors.Is(err, ErrDoesNotExist) && rt.Kind() == reflect.Pointer {
+		return c.choose(c, rt.Elem(), modifiers)
+	}
+
+	return ch, err
+}
+
+func (r *resolver) choose(ctn *container, ft reflect.Type, modifiers []Modifier) (ch choice, err error) {
+	ch = choice{
+		typ:  ft,
+		elem: ft,
+	}
+
+	switch {
+	case reflectContainerType.AssignableTo(ft):
+		ch.kind = choiceContainer
+		return ch, nil
+	case ft == reflectTagsType && ctn.def != nil:
+		ch.kind = choiceTags
+		return ch, nil
+	}
+
+	if elem, ok := providerElem(ft); ok {
+		ctn.mux.Lock()
+		var defs = ctn.defs.find(ft, nil)
+		ctn.mux.Unlock()
 
 		if len(defs) == 0 {
-			return NewTypeError(tv.Type(), ErrDoesNotExist)
+			ch.kind, ch.elem = choiceProvider, elem
 		}
 	}
 
-	if ft.Kind() != reflect.Slice && len(defs) > 1 {
-		return NewTypeError(tv.Type(), ErrMultipleDefinitions)
+	if elem, ok := lazyElem(ft); ok {
+		ch.kind, ch.elem = choiceLazy, elem
 	}
 
-	for _, def := range defs {
-		if ctn.cycle.Has(def.id) {
-			return NewTypeError(tv.Type(), ErrCycleDetected)
-		}
+	ch.defs, ch.keyed, err = r.lookup(ctn, ch.elem, modifiers)
 
-		if !def.unshared {
-			ctn.mux.Lock()
-			var dep, ok = ctn.cache[def.id]
-			ctn.mux.Unlock()
+	return ch, err
+}
 
-			if ok {
-				<-dep.ready
-				r.set(tv, dep.value)
-				continue
+func (r *resolver) lookup(ctn *container, ft reflect.Type, modifiers []Modifier) ([]definition, bool, error) {
+	if (ft.Kind() == reflect.Slice || isKeyed(ft)) && ctn.order != nil {
+		modifiers = append([]Modifier{ctn.order}, modifiers...)
+	}
+
+	ctn.mux.Lock()
+	defer ctn.mux.Unlock()
+
+	return ctn.defs.lookup(ft, modifiers)
+}
+
+func (r *resolver) lazy(ctn *container, ft reflect.Type, elem reflect.Type, modifiers []Modifier) reflect.Value {
+	var (
+		mods = append([]Modifier(nil), modifiers...)
+		lv   = reflect.New(ft)
+	)
+
+	lv.Interface().(lazyBinder).bind(&lazy{
+		resolve: func() (any, error) {
+			if !ctn.begin() {
+				return nil, NewTypeError(elem, ErrClosed)
 			}
 
-			ctn.mux.Lock()
-			ctn.cache[def.id] = &cacheItem{
-				ready: make(chan struct{}),
+			defer ctn.end()
+
+			var (
+				target = reflect.New(elem)
+				rc     = ctn.deferred()
+			)
+
+			if err := rc.resolve(rc, &target, mods); err != nil {
+				return nil, err
 			}
-			ctn.mux.Unlock()
+
+			return target.Elem().Interface(), nil
+		},
+	})
+
+	return lv.Elem()
+}
+
+func (r *resolver) provider(ctn *container, ft reflect.Type, elem reflect.Type, modifiers []Modifier) reflect.Value {
+	var mods = append([]Modifier(nil), modifiers...)
+	return reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		if !ctn.begin() {
+			var err error = fmt.Errorf("%s : %w", runtime.Caller(0), NewTypeError(elem, ErrClosed))
+			return []reflect.Value{reflect.Zero(elem), reflect.ValueOf(&err).Elem()}
 		}
 
-		var deps = def.compiler.Dependencies()
-		for _, dep := range deps {
-			var newCtn = &container{
-				containerCore: ctn.containerCore,
-				cycle:         ctn.cycle.Append(def.id),
-			}
+		defer ctn.end()
 
-			if err = r.resolveDependency(newCtn, dep, def.constraints); err != nil {
-				return err
-			}
+		var (
+			target = reflect.New(elem)
+			rc     = ctn.deferred()
+		)
+
+		if err := rc.resolve(rc, &target, mods); err != nil {
+			err = fmt.Errorf("%s : %w", runtime.Caller(0), err)
+			return []reflect.Value{reflect.Zero(elem), reflect.ValueOf(&err).Elem()}
 		}
 
-		var sv, closer, err = def.compiler.Create(deps...)
-		if err != nil {
-			return NewTypeError(def.compiler.Type(), err)
+		return []reflect.Value{target.Elem(), reflect.Zero(reflectErrorType)}
+	})
+}
+
+// deferred returns the container resolving Lazy and Provider of the container. After the construction has
+// finished, the resolution does not belong to it, so the cycle starts over and the definition only records
+// its dependencies.
+func (c *container) deferred() *container {
+	if c.def == nil || atomic.LoadInt32(&c.built) == 0 {
+		return c
+	}
+
+	return &container{
+		containerCore: c.containerCore,
+		cycle:         cycle.New(),
+		overlay:       c.overlay,
+		workers:       c.workers,
+		dependent:     c.def,
+	}
+}
+
+// resolveDependencies resolves the dependencies in order. With the Parallel option the free workers take
+// sibling dependencies, the rest are resolved in place, so nested resolutions never wait for a worker.
+// The error of the first failed dependency is returned regardless of the completion order.
+func (r *resolver) resolveDependencies(ctn *container, deps []*compiler.Dependency, cs constraints, plans []plan) error {
+	var (
+		errs = make([]error, len(deps))
+		wg   sync.WaitGroup
+	)
+
+	for i, dep := range deps {
+		var p *plan
+		if plans != nil {
+			p = &plans[i]
 		}
 
-		if !def.unshared {
-			ctn.mux.Lock()
-			ctn.cache[def.id].value = sv
-			close(ctn.cache[def.id].ready)
-			ctn.mux.Unlock()
+		if len(deps) > 1 && ctn.acquire() {
+			wg.Add(1)
+			go func(i int, dep *compiler.Dependency) {
+				defer wg.Done()
+				defer ctn.release()
+				defer r.recover(&errs[i])
+
+				errs[i] = r.resolveDependency(ctn, dep, cs, p)
+			}(i, dep)
+
+			continue
 		}
 
-		if closer != nil {
-			ctn.mux.Lock()
-			ctn.closers = append(ctn.closers, closer)
-			ctn.mux.Unlock()
+		if errs[i] = r.resolveDependency(ctn, dep, cs, p); errs[i] != nil {
+			break
 		}
+	}
 
-		r.set(tv, sv)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (r *resolver) resolveDependency(ctn *container, dep *compiler.Dependency, cs constraints) error {
+func (r *resolver) resolveDependency(ctn *container, dep *compiler.Dependency, cs constraints, p *plan) error {
 	var v = &dep.Value
 	if v.CanAddr() {
 		v = &[]reflect.Value{v.Addr()}[0]
 	}
 
-	var (
-		constr = cs.choose(dep.Index, dep.Name, dep.Type)
-		err    = ctn.resolve(ctn, v, constr.modifiers)
-	)
+	if p != nil {
+		return r.resolvePlan(ctn, v, p)
+	}
+
+	if isIn(dep.Type) {
+		return r.resolveIn(ctn, v)
+	}
+
+	return r.resolveConstraint(ctn, v, cs.choose(dep.Index, dep.Name, dep.Type))
+}
+
+func (r *resolver) resolveIn(ctn *container, v *reflect.Value) error {
+	for _, field := range inFields(v.Type().Elem()) {
+		var constr, err = inConstraint(field)
+		if err != nil {
+			return NewTypeError(v.Type().Elem(), err)
+		}
+
+		var fv = v.Elem().FieldByIndex(field.Index).Addr()
+		if err = r.resolveConstraint(ctn, &fv, constr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
+func (r *resolver) resolveConstraint(ctn *container, v *reflect.Value, constr constraint) error {
+	var err = ctn.resolve(ctn, v, constr.modifiers)
 	if errors.Is(err, ErrDoesNotExist) && constr.optional {
 		if e, ok := err.(*TypeError); ok && v.Type() != e.Type {
 			return err
@@ -274,6 +914,155 @@ func (r *resolver) resolveDependency(ctn *container, dep *compiler.Dependency, c
 	return err
 }
 
+// recover converts the panic to the error.
+func (r *resolver) recover(err *error) {
+	if recovered := recover(); recovered != nil {
+		*err = fmt.Errorf("unable to resolve target because the container panicked: %+v", recovered)
+	}
+}
+
+func (r *resolver) setKey(tv *reflect.Value, key string, keyed bool, sv reflect.Value) {
+	if !keyed {
+		r.set(tv, sv)
+		return
+	}
+
+	tv.Elem().SetMapIndex(reflect.ValueOf(key).Convert(tv.Type().Elem().Key()), sv)
+}
+
+// depend records that the definition of the container depends on the def.
+func (r *resolver) depend(ctn *container, def *definition) {
+	var dependent = ctn.def
+	if dependent == nil {
+		dependent = ctn.dependent
+	}
+
+	if dependent == nil {
+		return
+	}
+
+	ctn.mux.Lock()
+	defer ctn.mux.Unlock()
+
+	var st = ctn.state(def.id)
+	if st.dependents == nil {
+		st.dependents = make(map[int]bool)
+	}
+
+	st.dependents[dependent.id] = true
+}
+
+// begin registers the in-flight resolution, it returns false if the container is closed.
+func (c *containerCore) begin() bool {
+	for {
+		var active = atomic.LoadInt64(&c.active)
+		if active&closedBit != 0 {
+			return false
+		}
+
+		if atomic.CompareAndSwapInt64(&c.active, active, active+1) {
+			return true
+		}
+	}
+}
+
+// end unregisters the in-flight resolution, the last one of the closed container lets Close run closers.
+func (c *containerCore) end() {
+	if atomic.AddInt64(&c.active, -1) == closedBit {
+		close(c.idle)
+	}
+}
+
+// shutdown marks the container closed, it returns false if the container has already been closed.
+func (c *containerCore) shutdown() bool {
+	for {
+		var active = atomic.LoadInt64(&c.active)
+		if active&closedBit != 0 {
+			return false
+		}
+
+		if atomic.CompareAndSwapInt64(&c.active, active, active|closedBit) {
+			if active == 0 {
+				close(c.idle)
+			}
+
+			return true
+		}
+	}
+}
+
+// cycleError returns ErrCycleDetected with types of the definitions on the cycle path.
+func (c *containerCore) cycleError(ids []int) error {
+	var names = make([]string, 0, len(ids))
+	for _, id := range ids {
+		names = append(names, c.index[id].Type().String())
+	}
+
+	return fmt.Errorf("%w : %s", ErrCycleDetected, strings.Join(names, " -> "))
+}
+
+// acquire takes the free worker if any.
+func (c *container) acquire() bool {
+	if c.workers == nil {
+		return false
+	}
+
+	select {
+	case c.workers <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release returns the worker taken by acquire.
+func (c *container) release() {
+	<-c.workers
+}
+
+// state returns the definition state, must be called under the lock.
+func (c *containerCore) state(id int) *state {
+	var st = c.states[id]
+	if st == nil {
+		st = &state{}
+		c.states[id] = st
+	}
+
+	return st
+}
+
+// acquire returns the cache item of the slot, if the slot is empty or the last construction failed,
+// the new item is published and the caller becomes its owner responsible for the construction.
+func (s *cacheSlot) acquire() (_ *cacheItem, owner bool) {
+	for {
+		var (
+			old     = s.item.Load()
+			item, _ = old.(*cacheItem)
+		)
+
+		if item != nil && (!item.done() || item.err == nil) {
+			return item, false
+		}
+
+		var fresh = &cacheItem{
+			ready: make(chan struct{}),
+		}
+
+		if s.item.CompareAndSwap(old, fresh) {
+			return fresh, true
+		}
+	}
+}
+
+func (i *cacheItem) done() bool {
+	select {
+	case <-i.ready:
+		return true
+	default:
+		return false
+	}
+}
+
 func (r *resolver) set(tv *reflect.Value, sv reflect.Value) {
 	switch tv.Elem().Kind() {
 	case reflect.Slice:
@@ -290,3 +1079,52 @@ func (r *resolver) set(tv *reflect.Value, sv reflect.Value) {
 		tv.Elem().Set(sv)
 	}
 }
+
+func (w waits) add(from, to int) {
+	if w[from] == nil {
+		w[from] = make(map[int]int)
+	}
+
+	w[from][to]++
+}
+
+func (w waits) remove(from, to int) {
+	if w[from][to]--; w[from][to] == 0 {
+		delete(w[from], to)
+	}
+
+	if len(w[from]) == 0 {
+		delete(w, from)
+	}
+}
+
+// path returns ids of the definitions on the way between the definitions, or nil if there is no way.
+func (w waits) path(from, to int) []int {
+	var (
+		stack  = []int{from}
+		parent = map[int]int{from: from}
+	)
+
+	for len(stack) > 0 {
+		var id = stack[len(stack)-1]
+		if id == to {
+			var path = []int{id}
+			for id != from {
+				id = parent[id]
+				path = append([]int{id}, path...)
+			}
+
+			return path
+		}
+
+		stack = stack[:len(stack)-1]
+		for next := range w[id] {
+			if _, ok := parent[next]; !ok {
+				parent[next] = id
+				stack = append(stack, next)
+			}
+		}
+	}
+
+	return nil
+}
@@ -8,10 +8,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gozix/di"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -359,3 +364,1566 @@ func TestContainer(t *testing.T) {
 		})
 	}
 }
+
+func TestContainer_Outputs(t *testing.T) {
+	type Result struct {
+		di.Out
+
+		Bar *BarController
+		Baz *BazController `tags:"baz"`
+	}
+
+	var (
+		calls   = 0
+		closers = 0
+	)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() (Items, []Item, func() error, error) {
+			calls++
+			return Items{1}, []Item{2}, func() error {
+				closers++
+				return nil
+			}, nil
+		}),
+		di.Provide(func() Result {
+			calls++
+			return Result{
+				Bar: NewBarController(),
+				Baz: NewBazController(),
+			}
+		}, di.Fields{
+			"Bar": {di.As(new(Controller))},
+		}),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	err = ctn.Call(func(s1 Items, s2 []Item, bar *BarController, controller Controller, baz *BazController) {
+		require.Equal(t, Items{1}, s1)
+		require.Equal(t, []Item{2}, s2)
+		require.Same(t, bar, controller)
+		require.NotNil(t, baz)
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.True(t, ctn.Has((*BazController)(nil), di.WithTags("baz")))
+
+	require.NoError(t, ctn.Close())
+	require.Equal(t, 1, closers)
+
+	_, err = di.NewBuilder(
+		di.Provide(func() Result {
+			return Result{}
+		}, di.Fields{
+			"Unknown": {di.As(new(Controller))},
+		}),
+	)
+
+	require.ErrorIs(t, err, di.ErrDoesNotExist)
+}
+
+func TestContainer_In(t *testing.T) {
+	type (
+		Params struct {
+			di.In
+
+			Bar         *BarController
+			Server      *http.Server `optional:"true"`
+			Baz         Controller   `tags:"baz"`
+			Controllers []Controller `group:"controller"`
+			Empty       []Controller `group:"empty"`
+		}
+
+		Invalid struct {
+			di.In
+
+			Bar *BarController `optional:"maybe"`
+		}
+	)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Controller)), di.Tags{{Name: "controller"}}),
+		di.Provide(NewBazController, di.As(new(Controller)), di.Tags{{Name: "controller"}, {Name: "baz"}}),
+		di.Provide(func(p Params) *ManualResolver {
+			return &ManualResolver{bar: p.Bar}
+		}),
+	)
+
+	require.NoError(t, err)
+	for _, def := range builder.Definitions() {
+		if def.Type().String() == "*di_test.ManualResolver" {
+			require.Len(t, def.Dependencies(), 5)
+		}
+	}
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	err = ctn.Call(func(p Params, mr *ManualResolver) {
+		require.NotNil(t, p.Bar)
+		require.Nil(t, p.Server)
+		require.IsType(t, (*BazController)(nil), p.Baz)
+		require.Len(t, p.Controllers, 2)
+		require.Len(t, p.Empty, 0)
+		require.Same(t, p.Bar, mr.bar)
+	})
+
+	require.NoError(t, err)
+	require.Error(t, ctn.Call(func(Invalid) {}))
+
+	_, err = di.NewBuilder(
+		di.Provide(func(Invalid) *ManualResolver {
+			return nil
+		}),
+	)
+
+	require.Error(t, err)
+	require.ErrorContains(t, err, "optional tag")
+}
+
+func TestContainer_Named(t *testing.T) {
+	type Params struct {
+		di.In
+
+		Primary Controller `name:"primary"`
+	}
+
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Controller)), di.Name("primary")),
+		di.Provide(NewBazController, di.As(new(Controller)), di.Name("primary-replica")),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var controller Controller
+	require.NoError(t, ctn.Resolve(&controller, di.Named("primary")))
+	require.IsType(t, (*BarController)(nil), controller)
+	require.ErrorIs(t, ctn.Resolve(&controller, di.Named("replica")), di.ErrDoesNotExist)
+
+	err = ctn.Call(func(p Params) {
+		require.IsType(t, (*BarController)(nil), p.Primary)
+	})
+
+	require.NoError(t, err)
+}
+
+func TestContainer_TagArgs(t *testing.T) {
+	type Route struct {
+		Path string
+	}
+
+	var newRoute = func(tags di.Tags) *Route {
+		var tag, _ = tags.Find("handler")
+		var path, _ = tag.Args.Value("path")
+
+		return &Route{Path: path}
+	}
+
+	var builder, err = di.NewBuilder(
+		di.Provide(newRoute, di.Unshared(), di.Tags{{
+			Name: "handler",
+			Args: di.Args{{Key: "path", Value: "/api"}},
+		}}),
+		di.Provide(newRoute, di.Unshared(), di.Tags{{
+			Name: "handler",
+			Args: di.Args{{Key: "path", Value: "/api/v2"}},
+		}}),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var route *Route
+	require.NoError(t, ctn.Resolve(&route, di.WithTagArg("handler", "path", "/api")))
+	require.Equal(t, "/api", route.Path)
+
+	var routes []*Route
+	require.NoError(t, ctn.Resolve(&routes, di.WithTagArgMatch("handler", "path", func(value string) bool {
+		return strings.HasPrefix(value, "/api")
+	})))
+	require.Len(t, routes, 2)
+
+	require.ErrorIs(t, ctn.Resolve(&route, di.WithTagArg("handler", "path", "/")), di.ErrDoesNotExist)
+	require.ErrorIs(t, ctn.Call(func(di.Tags) {}), di.ErrDoesNotExist)
+}
+
+func TestContainer_Priority(t *testing.T) {
+	var newItem = func(value Item) func() Item {
+		return func() Item {
+			return value
+		}
+	}
+
+	var testCases = []struct {
+		Name     string
+		Options  []di.BuilderOption
+		Expected []Item
+	}{{
+		Name: "Default order",
+		Options: []di.BuilderOption{
+			di.Provide(newItem(1)),
+			di.Provide(newItem(2), di.Priority(10)),
+			di.Provide(newItem(3)),
+			di.Provide(newItem(4), di.Priority(-1)),
+			di.Add(Item(5), di.Priority(10)),
+		},
+		Expected: []Item{2, 5, 1, 3, 4},
+	}, {
+		Name: "Registration order",
+		Options: []di.BuilderOption{
+			di.DefaultOrder(nil),
+			di.Provide(newItem(1)),
+			di.Provide(newItem(2), di.Priority(10)),
+			di.Provide(newItem(3)),
+		},
+		Expected: []Item{1, 2, 3},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var builder, err = di.NewBuilder(tc.Options...)
+			require.NoError(t, err)
+
+			var ctn di.Container
+			ctn, err = builder.Build()
+			require.NoError(t, err)
+
+			var items []Item
+			require.NoError(t, ctn.Resolve(&items))
+			require.Equal(t, tc.Expected, items)
+
+			items = nil
+			require.NoError(t, ctn.Resolve(&items, di.Sort(func(a, b di.Definition) bool {
+				return a.ID() > b.ID()
+			})))
+			require.Len(t, items, len(tc.Expected))
+			require.Equal(t, Item(len(tc.Expected)), items[0])
+		})
+	}
+}
+
+func TestContainer_Map(t *testing.T) {
+	type Handler interface {
+		Register(server *http.ServeMux)
+	}
+
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Handler)), di.Name("bar"), di.Tags{{
+			Name: "handler",
+			Args: di.Args{{Key: "path", Value: "/bar"}},
+		}}),
+		di.Provide(NewBazController, di.As(new(Handler)), di.Name("baz"), di.Tags{{
+			Name: "handler",
+			Args: di.Args{{Key: "path", Value: "/bar"}},
+		}}),
+		di.Add(&FlakyController{}, di.As(new(Handler))),
+		di.Provide(func(handlers map[string]Handler) *http.ServeMux {
+			var mux = http.NewServeMux()
+			for _, h := range handlers {
+				h.Register(mux)
+			}
+
+			return mux
+		}),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var handlers map[string]Handler
+	require.NoError(t, ctn.Resolve(&handlers))
+	require.Len(t, handlers, 2)
+	require.IsType(t, (*BarController)(nil), handlers["bar"])
+	require.IsType(t, (*BazController)(nil), handlers["baz"])
+
+	var mux *http.ServeMux
+	require.NoError(t, ctn.Resolve(&mux))
+
+	handlers = nil
+	require.NoError(t, ctn.Resolve(&handlers, di.KeyBy(func(def di.Definition) string {
+		return def.Type().String()
+	})))
+	require.Len(t, handlers, 3)
+
+	handlers = nil
+	err = ctn.Resolve(&handlers, di.KeyByTagArg("handler", "path"))
+	require.ErrorIs(t, err, di.ErrDuplicateKey)
+	require.ErrorContains(t, err, "container_test.go:625")
+	require.ErrorContains(t, err, "container_test.go:629")
+}
+
+func TestContainer_Lazy(t *testing.T) {
+	type (
+		Service struct {
+			Bar di.Lazy[*BarController]
+			Baz di.Lazy[*BazController]
+		}
+
+		Child struct {
+			Parent di.Lazy[*Service]
+		}
+	)
+
+	var (
+		created = 0
+		cycled  di.Lazy[*ManualResolver]
+	)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() *BarController {
+			created++
+			return NewBarController()
+		}, di.Unshared()),
+		di.Autowire((*Service)(nil), di.Constraint("Baz", di.Optional(true))),
+		di.Autowire((*Child)(nil)),
+		di.Provide(func(*CycledController) *ManualResolver {
+			return &ManualResolver{}
+		}),
+		di.Provide(func(lazy di.Lazy[*ManualResolver]) *CycledController {
+			cycled = lazy
+			return &CycledController{}
+		}),
+		di.Provide(func(lazy di.Lazy[*FlakyController]) (*FlakyController, error) {
+			var _, err = lazy.Get()
+			return &FlakyController{}, err
+		}),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var service *Service
+	require.NoError(t, ctn.Resolve(&service))
+	require.Equal(t, 0, created)
+
+	var bar1, bar2 *BarController
+	bar1, err = service.Bar.Get()
+	require.NoError(t, err)
+	bar2, err = service.Bar.Get()
+	require.NoError(t, err)
+	require.Same(t, bar1, bar2)
+	require.Equal(t, 1, created)
+
+	_, err = service.Baz.Get()
+	require.ErrorIs(t, err, di.ErrDoesNotExist)
+
+	err = ctn.Call(func(child *Child, lazy di.Lazy[*Service]) {
+		var parent, err = child.Parent.Get()
+		require.NoError(t, err)
+		require.Same(t, service, parent)
+
+		parent, err = lazy.Get()
+		require.NoError(t, err)
+		require.Same(t, service, parent)
+	})
+
+	require.NoError(t, err)
+	require.ErrorIs(t, ctn.Call(func(di.Lazy[*http.Server]) {}), di.ErrDoesNotExist)
+
+	var mr1, mr2 *ManualResolver
+	require.NoError(t, ctn.Resolve(&mr1))
+	mr2, err = cycled.Get()
+	require.NoError(t, err)
+	require.Same(t, mr1, mr2)
+
+	var flaky *FlakyController
+	require.ErrorIs(t, ctn.Resolve(&flaky), di.ErrCycleDetected)
+	require.ErrorIs(t, ctn.Resolve(&flaky), di.ErrCycleDetected)
+}
+
+func TestContainer_Provider(t *testing.T) {
+	type Handler struct {
+		NewBar      di.Provider[*BarController]
+		NewBaz      func() (*BazController, error)
+		Controllers func() ([]Controller, error)
+	}
+
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.Unshared(), di.As(new(Controller))),
+		di.Provide(NewBazController, di.As(new(Controller))),
+		di.Autowire((*Handler)(nil)),
+		di.Add(func() (*http.Server, error) {
+			return nil, errors.New("registered")
+		}),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var handler *Handler
+	require.NoError(t, ctn.Resolve(&handler))
+
+	var bar1, bar2 *BarController
+	bar1, err = handler.NewBar()
+	require.NoError(t, err)
+	bar2, err = handler.NewBar()
+	require.NoError(t, err)
+	require.NotSame(t, bar1, bar2)
+
+	var baz1, baz2 *BazController
+	baz1, err = handler.NewBaz()
+	require.NoError(t, err)
+	baz2, err = handler.NewBaz()
+	require.NoError(t, err)
+	require.Same(t, baz1, baz2)
+
+	var controllers []Controller
+	controllers, err = handler.Controllers()
+	require.NoError(t, err)
+	require.Len(t, controllers, 2)
+
+	err = ctn.Call(func(newServer func() (*http.Server, error)) {
+		var _, err = newServer()
+		require.EqualError(t, err, "registered")
+	})
+
+	require.NoError(t, err)
+	require.ErrorIs(t, ctn.Call(func(di.Provider[*http.ServeMux]) {}), di.ErrDoesNotExist)
+}
+
+func TestContainer_Factory(t *testing.T) {
+	type (
+		UserID string
+
+		Session struct {
+			Bar    *BarController
+			UserID UserID
+			Role   string
+		}
+	)
+
+	var newSession = func(bar *BarController, userID UserID, role string) (*Session, error) {
+		if userID == "" {
+			return nil, errors.New("empty user id")
+		}
+
+		return &Session{Bar: bar, UserID: userID, Role: role}, nil
+	}
+
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController),
+		di.Factory(newSession, di.Assisted(UserID(""), 2)),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	err = ctn.Call(func(bar *BarController, factory func(UserID, string) (*Session, error)) {
+		var session, err = factory("user", "admin")
+		require.NoError(t, err)
+		require.Same(t, bar, session.Bar)
+		require.Equal(t, UserID("user"), session.UserID)
+		require.Equal(t, "admin", session.Role)
+
+		_, err = factory("", "admin")
+		require.EqualError(t, err, "empty user id")
+	})
+
+	require.NoError(t, err)
+
+	_, err = di.NewBuilder(
+		di.Factory(newSession, di.Assisted(3)),
+	)
+
+	require.ErrorIs(t, err, di.ErrDoesNotExist)
+}
+
+func TestContainer_Has(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Controller)), di.Name("bar"), di.Tags{{Name: "bar"}}),
+		di.Provide(NewBazController, di.As(new(Controller)), di.Priority(10)),
+		di.Provide(NewSlice1),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	type testCase struct {
+		Name      string
+		Value     di.Type
+		Modifiers []di.Modifier
+		Count     int
+		Has       bool
+		Explain   string
+	}
+
+	var testCases = []testCase{{
+		Name:    "Pointer",
+		Value:   (*BarController)(nil),
+		Count:   1,
+		Has:     true,
+		Explain: "#1 *di_test.BarController name \"bar\" tags [bar] provided at",
+	}, {
+		Name:    "Pointer to interface",
+		Value:   new(Controller),
+		Count:   2,
+		Explain: "error : multiple definitions",
+	}, {
+		Name:      "Pointer to interface with modifiers",
+		Value:     new(Controller),
+		Modifiers: []di.Modifier{di.WithTags("bar")},
+		Count:     1,
+		Has:       true,
+		Explain:   "with 1 modifier(s), 1 definition(s) chosen",
+	}, {
+		Name:    "Slice",
+		Value:   (*[]Controller)(nil),
+		Count:   2,
+		Has:     true,
+		Explain: "slice of di_test.Controller definitions",
+	}, {
+		Name:    "Slice definition",
+		Value:   []Item(nil),
+		Count:   1,
+		Has:     true,
+		Explain: "all []di_test.Item definitions",
+	}, {
+		Name:    "Map",
+		Value:   (*map[string]Controller)(nil),
+		Count:   1,
+		Has:     true,
+		Explain: "map of di_test.Controller definitions by key",
+	}, {
+		Name:    "Container",
+		Value:   (*di.Container)(nil),
+		Count:   1,
+		Has:     true,
+		Explain: "resolved by the container itself",
+	}, {
+		Name:    "Lazy",
+		Value:   (*di.Lazy[*BazController])(nil),
+		Count:   1,
+		Has:     true,
+		Explain: "priority 10",
+	}, {
+		Name:    "Provider",
+		Value:   (*di.Provider[*BarController])(nil),
+		Count:   1,
+		Has:     true,
+		Explain: "resolved by provider function of *di_test.BarController",
+	}, {
+		Name:    "Missing",
+		Value:   (*http.Server)(nil),
+		Count:   0,
+		Explain: "error : does not exist",
+	}, {
+		Name:    "Nil",
+		Value:   nil,
+		Count:   0,
+		Explain: "invalid type",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Count, ctn.Count(tc.Value, tc.Modifiers...))
+			require.Equal(t, tc.Has, ctn.Has(tc.Value, tc.Modifiers...))
+			require.Contains(t, ctn.Explain(tc.Value, tc.Modifiers...), tc.Explain)
+		})
+	}
+}
+
+func TestContainer_State(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Controller))),
+		di.Provide(NewServerMux),
+		di.Provide(NewServer, di.Unshared()),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var defs = ctn.Definitions()
+	require.Len(t, defs, 3)
+
+	for _, def := range defs {
+		require.Equal(t, di.State{}, ctn.State(def))
+	}
+
+	var before = time.Now()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, ctn.Resolve(new(*http.Server)))
+	}
+
+	var (
+		bar    = ctn.State(defs[0])
+		mux    = ctn.State(defs[1])
+		server = ctn.State(defs[2])
+	)
+
+	require.True(t, bar.Instantiated)
+	require.Equal(t, 1, bar.Instances)
+	require.False(t, bar.CreatedAt.Before(before))
+	require.False(t, bar.Closer)
+	require.Len(t, bar.Dependents, 1)
+	require.Equal(t, defs[1].ID(), bar.Dependents[0].ID())
+
+	require.Equal(t, 1, mux.Instances)
+	require.True(t, mux.Closer)
+	require.Len(t, mux.Dependents, 1)
+	require.Equal(t, defs[2].ID(), mux.Dependents[0].ID())
+
+	require.Equal(t, 2, server.Instances)
+	require.Empty(t, server.Dependents)
+
+	require.NoError(t, ctn.Close())
+}
+
+func TestContainer_Stats(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Controller))),
+		di.Provide(NewServerMux),
+		di.Provide(NewServer, di.Unshared()),
+		di.Provide(NewFlakyController),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+	require.Equal(t, di.Stats{Definitions: 4}, ctn.Stats())
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, ctn.Resolve(new(*http.Server)))
+	}
+
+	require.Error(t, ctn.Resolve(new(*FlakyController)))
+	require.NoError(t, ctn.Close())
+
+	var stats = ctn.Stats()
+	require.Equal(t, uint64(4), stats.Instances)
+	require.Equal(t, uint64(1), stats.CacheHits)
+	require.Equal(t, uint64(3), stats.CacheMisses)
+	require.Equal(t, uint64(1), stats.Failures)
+	require.Equal(t, uint64(1), stats.ClosersRegistered)
+	require.Equal(t, uint64(1), stats.ClosersRun)
+	require.Positive(t, stats.ConstructionTime)
+}
+
+func TestContainer_Parallel(t *testing.T) {
+	type (
+		Shared struct{}
+		Client struct{ Shared *Shared }
+		Cycle1 struct{}
+		Cycle2 struct{}
+		Root   struct{}
+	)
+
+	var newBuilder = func(t *testing.T, options ...di.BuilderOption) di.Container {
+		var builder, err = di.NewBuilder(append([]di.BuilderOption{di.Parallel(4)}, options...)...)
+		require.NoError(t, err)
+
+		var ctn di.Container
+		ctn, err = builder.Build()
+		require.NoError(t, err)
+
+		return ctn
+	}
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var (
+			started = make(chan struct{}, 3)
+			calls   = 0
+		)
+
+		var newClient = func(shared *Shared) (*Client, error) {
+			started <- struct{}{}
+			var deadline = time.Now().Add(time.Second)
+			for len(started) < cap(started) {
+				if time.Now().After(deadline) {
+					return nil, errors.New("dependencies are resolved sequentially")
+				}
+
+				time.Sleep(time.Millisecond)
+			}
+
+			return &Client{Shared: shared}, nil
+		}
+
+		var ctn = newBuilder(t,
+			di.Provide(func() *Shared {
+				calls++
+				return &Shared{}
+			}),
+			di.Provide(newClient, di.Tags{{Name: "a"}}),
+			di.Provide(newClient, di.Tags{{Name: "b"}}),
+			di.Provide(newClient, di.Tags{{Name: "c"}}),
+		)
+
+		var err = ctn.Call(func(a, b, c *Client) {
+			require.Same(t, a.Shared, b.Shared)
+			require.Same(t, a.Shared, c.Shared)
+		}, di.Constraint(0, di.WithTags("a")), di.Constraint(1, di.WithTags("b")), di.Constraint(2, di.WithTags("c")))
+
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			var ctn = newBuilder(t,
+				di.Provide(func(*Cycle2) *Cycle1 { return &Cycle1{} }),
+				di.Provide(func(*Cycle1) *Cycle2 { return &Cycle2{} }),
+				di.Provide(func(*Cycle1, *Cycle2) *Root { return &Root{} }),
+			)
+
+			require.ErrorIs(t, ctn.Resolve(new(*Root)), di.ErrCycleDetected)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			var ctn = newBuilder(t,
+				di.Provide(func() (*Cycle1, error) {
+					time.Sleep(time.Millisecond)
+					return nil, errors.New("first")
+				}),
+				di.Provide(func() (*Cycle2, error) { return nil, errors.New("second") }),
+				di.Provide(func(*Cycle1, *Cycle2) *Root { return &Root{} }),
+			)
+
+			require.ErrorContains(t, ctn.Resolve(new(*Root)), "first")
+		}
+	})
+
+	t.Run("Call", func(t *testing.T) {
+		var started = make(chan struct{}, 2)
+		var newClient = func() (*Client, error) {
+			started <- struct{}{}
+			var deadline = time.Now().Add(time.Second)
+			for len(started) < cap(started) {
+				if time.Now().After(deadline) {
+					return nil, errors.New("arguments are resolved sequentially")
+				}
+
+				time.Sleep(time.Millisecond)
+			}
+
+			return &Client{}, nil
+		}
+
+		var ctn = newBuilder(t,
+			di.Parallel(0),
+			di.Provide(newClient, di.Tags{{Name: "a"}}),
+			di.Provide(newClient, di.Tags{{Name: "b"}}),
+		)
+
+		var err = ctn.Call(func(a, b *Client) {
+			require.NotSame(t, a, b)
+		}, di.Constraint(0, di.WithTags("a")), di.Constraint(1, di.WithTags("b")), di.Parallel(2))
+
+		require.NoError(t, err)
+		require.ErrorIs(t, ctn.Call(func() {}, di.Parallel(-1)), di.ErrInvalidValue)
+	})
+
+	var _, err = di.NewBuilder(di.Parallel(-1))
+	require.ErrorIs(t, err, di.ErrInvalidValue)
+}
+
+func TestContainer_Concurrent(t *testing.T) {
+	var (
+		calls    int32
+		failures int32
+	)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() *BarController {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(time.Millisecond)
+			return NewBarController()
+		}),
+		di.Provide(func() (*FlakyController, error) {
+			if atomic.AddInt32(&failures, 1) <= 5 {
+				return nil, errors.New("flaky")
+			}
+
+			return &FlakyController{}, nil
+		}),
+		di.Provide(NewBazController, di.Unshared()),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var (
+		wg   sync.WaitGroup
+		bars = make([]*BarController, 50)
+	)
+
+	for i := range bars {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			assert.NoError(t, ctn.Resolve(&bars[i]))
+
+			var baz *BazController
+			assert.NoError(t, ctn.Resolve(&baz))
+
+			var flaky *FlakyController
+			_ = ctn.Resolve(&flaky)
+		}(i)
+	}
+
+	wg.Wait()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, bar := range bars {
+		require.Same(t, bars[0], bar)
+	}
+
+	var flaky *FlakyController
+	require.NoError(t, ctn.Resolve(&flaky))
+	require.NotNil(t, flaky)
+	require.NoError(t, ctn.Close())
+}
+
+func TestContainer_CrossCycle(t *testing.T) {
+	type (
+		Barrier1 struct{}
+		Barrier2 struct{}
+		Cycle1   struct{}
+		Cycle2   struct{}
+	)
+
+	var (
+		barrier sync.WaitGroup
+		wait    = func() {
+			barrier.Done()
+			barrier.Wait()
+		}
+	)
+
+	barrier.Add(2)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() *Barrier1 { wait(); return &Barrier1{} }),
+		di.Provide(func() *Barrier2 { wait(); return &Barrier2{} }),
+		di.Provide(func(*Barrier1, *Cycle2) *Cycle1 { return &Cycle1{} }),
+		di.Provide(func(*Barrier2, *Cycle1) *Cycle2 { return &Cycle2{} }),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var errs = make(chan error, 2)
+	go func() { errs <- ctn.Resolve(new(*Cycle1)) }()
+	go func() { errs <- ctn.Resolve(new(*Cycle2)) }()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err = <-errs:
+			require.ErrorIs(t, err, di.ErrCycleDetected)
+			require.Regexp(t, `\*di_test.Cycle\d -> \*di_test.Cycle\d -> \*di_test.Cycle\d`, err.Error())
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "deadlock")
+		}
+	}
+}
+
+func TestContainer_Typed(t *testing.T) {
+	var closers = 0
+
+	var builder, err = di.NewBuilder(
+		di.Provide0(NewBarController),
+		di.Provide0E(NewFlakyController),
+		di.Provide1C(NewServerMux, di.Constraint(0, di.WithTags("controller"))),
+		di.Provide1(NewServer),
+		di.Provide0(func() Controller { return NewBazController() }, di.Tags{{Name: "controller"}}),
+		di.Provide2CE(func(bar *BarController, controller Controller) (*CycledController, func() error, error) {
+			return &CycledController{}, func() error {
+				closers++
+				return nil
+			}, nil
+		}),
+		di.Provide3(func(bar *BarController, mux *http.ServeMux, server *http.Server) Items {
+			return Items{1, 2, 3}
+		}),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	err = ctn.Call(func(server *http.Server, controller Controller, cycled *CycledController, items Items) {
+		require.NotNil(t, server.Handler)
+		require.IsType(t, &BazController{}, controller)
+		require.NotNil(t, cycled)
+		require.Equal(t, Items{1, 2, 3}, items)
+	})
+
+	require.NoError(t, err)
+	require.ErrorContains(t, ctn.Resolve(new(*FlakyController)), "always fail")
+	require.NoError(t, ctn.Close())
+	require.Equal(t, 1, closers)
+
+	var reflected di.Builder
+	reflected, err = di.NewBuilder(di.Provide(NewServerMux))
+	require.NoError(t, err)
+
+	builder, err = di.NewBuilder(di.Provide1C(NewServerMux))
+	require.NoError(t, err)
+
+	var (
+		expected = reflected.Definitions()[0]
+		actual   = builder.Definitions()[0]
+	)
+
+	require.Equal(t, expected.Type(), actual.Type())
+	require.Equal(t, expected.Dependencies()[0].Type, actual.Dependencies()[0].Type)
+	require.Equal(t, expected.Unshared(), actual.Unshared())
+}
+
+func TestContainer_Close(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		closers int32
+	)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() (*BarController, func() error) {
+			close(started)
+			<-release
+
+			return NewBarController(), func() error {
+				atomic.AddInt32(&closers, 1)
+				return nil
+			}
+		}),
+		di.Provide(NewBazController),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var (
+		lazy     di.Lazy[*BazController]
+		provider di.Provider[*BazController]
+	)
+
+	require.NoError(t, ctn.Resolve(&lazy))
+	require.NoError(t, ctn.Resolve(&provider))
+
+	var resolved = make(chan error)
+	go func() { resolved <- ctn.Resolve(new(*BarController)) }()
+
+	<-started
+
+	var closed = make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { closed <- ctn.Close() }()
+	}
+
+	select {
+	case <-ctn.Done():
+		require.FailNow(t, "container is closed before in-flight resolution")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-resolved)
+	require.NoError(t, <-closed)
+	require.NoError(t, <-closed)
+	require.NoError(t, ctn.Close())
+	require.Equal(t, int32(1), atomic.LoadInt32(&closers))
+
+	<-ctn.Done()
+
+	require.ErrorIs(t, ctn.Resolve(new(*BazController)), di.ErrClosed)
+	require.ErrorIs(t, ctn.Call(func(*BazController) {}), di.ErrClosed)
+	require.False(t, ctn.Has((*BazController)(nil)))
+
+	_, err = lazy.Get()
+	require.ErrorIs(t, err, di.ErrClosed)
+
+	_, err = provider()
+	require.ErrorIs(t, err, di.ErrClosed)
+}
+
+func TestContainer_Invalidate(t *testing.T) {
+	type (
+		Config  struct{ Version int }
+		Service struct{ Config *Config }
+		Other   struct{}
+	)
+
+	var (
+		version = 0
+		fail    = false
+		closed  []string
+	)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() (*Config, func() error) {
+			version++
+			var cfg = &Config{Version: version}
+			return cfg, func() error {
+				closed = append(closed, fmt.Sprintf("config %d", cfg.Version))
+				return nil
+			}
+		}),
+		di.Provide(func(cfg *Config) (*Service, func() error, error) {
+			if fail {
+				return nil, nil, errors.New("service failed")
+			}
+
+			return &Service{Config: cfg}, func() error {
+				closed = append(closed, fmt.Sprintf("service %d", cfg.Version))
+				return nil
+			}, nil
+		}),
+		di.Provide(func() *Other { return &Other{} }),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var (
+		service *Service
+		other   *Other
+	)
+
+	require.NoError(t, ctn.Resolve(&service))
+	require.NoError(t, ctn.Resolve(&other))
+
+	require.NoError(t, ctn.Invalidate((*Config)(nil)))
+	require.Equal(t, []string{"service 1", "config 1"}, closed)
+
+	var (
+		invalidated *Service
+		same        *Other
+	)
+
+	require.NoError(t, ctn.Resolve(&invalidated))
+	require.NoError(t, ctn.Resolve(&same))
+	require.Equal(t, 2, invalidated.Config.Version)
+	require.Same(t, other, same)
+
+	closed = nil
+	require.NoError(t, ctn.Refresh((*Config)(nil)))
+	require.Equal(t, []string{"service 2", "config 2"}, closed)
+
+	var refreshed *Service
+	require.NoError(t, ctn.Resolve(&refreshed))
+	require.Equal(t, 3, refreshed.Config.Version)
+
+	closed, fail = nil, true
+	require.ErrorContains(t, ctn.Refresh((*Config)(nil)), "service failed")
+	require.Equal(t, []string{"config 4"}, closed)
+
+	var kept *Service
+	require.NoError(t, ctn.Resolve(&kept))
+	require.Same(t, refreshed, kept)
+
+	require.ErrorIs(t, ctn.Invalidate((*http.Server)(nil)), di.ErrDoesNotExist)
+
+	closed = nil
+	require.NoError(t, ctn.Close())
+	require.Equal(t, []string{"service 3", "config 3"}, closed)
+	require.ErrorIs(t, ctn.Invalidate((*Config)(nil)), di.ErrClosed)
+}
+
+func TestContainer_InvalidateUnshared(t *testing.T) {
+	type (
+		Config  struct{ Version int }
+		Request struct{ Config *Config }
+		Handler struct{ Request *Request }
+	)
+
+	var (
+		version = 0
+		fail    = false
+		closed  []string
+	)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() *Config {
+			version++
+			return &Config{Version: version}
+		}),
+		di.Provide(func(cfg *Config) (*Request, func() error) {
+			return &Request{Config: cfg}, func() error {
+				closed = append(closed, fmt.Sprintf("request %d", cfg.Version))
+				return nil
+			}
+		}, di.Unshared()),
+		di.Provide(func(req *Request) (*Handler, func() error, error) {
+			if fail {
+				return nil, nil, errors.New("handler failed")
+			}
+
+			return &Handler{Request: req}, func() error {
+				closed = append(closed, fmt.Sprintf("handler %d", req.Config.Version))
+				return nil
+			}, nil
+		}),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var (
+		request *Request
+		handler *Handler
+	)
+
+	require.NoError(t, ctn.Resolve(&request))
+	require.NoError(t, ctn.Resolve(&handler))
+
+	require.NoError(t, ctn.Invalidate((*Config)(nil)))
+	require.Equal(t, []string{"handler 1"}, closed)
+	require.NoError(t, ctn.Release(request))
+	require.Equal(t, []string{"handler 1", "request 1"}, closed)
+
+	require.NoError(t, ctn.Resolve(&handler))
+
+	closed, fail = nil, true
+	require.ErrorContains(t, ctn.Refresh((*Config)(nil)), "handler failed")
+	require.Equal(t, []string{"request 3"}, closed)
+
+	closed = nil
+	require.NoError(t, ctn.Close())
+	require.Equal(t, []string{"handler 2", "request 2", "request 1"}, closed)
+}
+
+func TestContainer_InvalidateOutputs(t *testing.T) {
+	type (
+		Client struct{ Version int }
+		Admin  struct{ Version int }
+		Result struct {
+			di.Out
+
+			Client *Client
+			Admin  *Admin
+		}
+	)
+
+	var testCases = []struct {
+		Name        string
+		Constructor func(version *int, closed *[]int) di.Constructor
+	}{{
+		Name: "Outputs",
+		Constructor: func(version *int, closed *[]int) di.Constructor {
+			return func() (*Client, *Admin, func() error) {
+				*version++
+				var v = *version
+				return &Client{Version: v}, &Admin{Version: v}, func() error {
+					*closed = append(*closed, v)
+					return nil
+				}
+			}
+		},
+	}, {
+		Name: "Out",
+		Constructor: func(version *int, closed *[]int) di.Constructor {
+			return func() (Result, func() error) {
+				*version++
+				var v = *version
+				return Result{Client: &Client{Version: v}, Admin: &Admin{Version: v}}, func() error {
+					*closed = append(*closed, v)
+					return nil
+				}
+			}
+		},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var (
+				version = 0
+				closed  []int
+			)
+
+			var builder, err = di.NewBuilder(di.Provide(tc.Constructor(&version, &closed)))
+			require.NoError(t, err)
+
+			var ctn di.Container
+			ctn, err = builder.Build()
+			require.NoError(t, err)
+
+			var (
+				client *Client
+				admin  *Admin
+			)
+
+			require.NoError(t, ctn.Resolve(&client))
+			require.NoError(t, ctn.Resolve(&admin))
+
+			require.NoError(t, ctn.Invalidate(new(*Client)))
+			require.Equal(t, []int{1}, closed)
+
+			require.NoError(t, ctn.Resolve(&admin))
+			require.NoError(t, ctn.Resolve(&client))
+			require.Equal(t, 2, client.Version)
+			require.Equal(t, 2, admin.Version)
+
+			require.NoError(t, ctn.Refresh(new(*Admin)))
+			require.Equal(t, []int{1, 2}, closed)
+
+			var refreshed *Client
+			require.NoError(t, ctn.Resolve(&refreshed))
+			require.Equal(t, 3, refreshed.Version)
+			require.Equal(t, 3, version)
+
+			require.NoError(t, ctn.Close())
+			require.Equal(t, []int{1, 2, 3}, closed)
+		})
+	}
+}
+
+func TestContainer_Release(t *testing.T) {
+	type (
+		Conn    struct{ ID int }
+		Session struct{ Conn *Conn }
+		Pool    struct{}
+	)
+
+	var (
+		seq    = 0
+		closed []string
+	)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() (*Conn, func() error) {
+			seq++
+			var conn = &Conn{ID: seq}
+			return conn, func() error {
+				closed = append(closed, fmt.Sprintf("conn %d", conn.ID))
+				return nil
+			}
+		}, di.Unshared()),
+		di.Provide(func(conn *Conn) *Session {
+			return &Session{Conn: conn}
+		}, di.Unshared()),
+		di.Provide(func() (*Pool, func() error) {
+			return &Pool{}, func() error {
+				closed = append(closed, "pool")
+				return nil
+			}
+		}),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var (
+		first, second *Conn
+		session       *Session
+		pool          *Pool
+	)
+
+	require.NoError(t, ctn.Resolve(&first))
+	require.NoError(t, ctn.Resolve(&second))
+	require.NoError(t, ctn.Resolve(&session))
+	require.NoError(t, ctn.Resolve(&pool))
+
+	require.NoError(t, ctn.Release(first))
+	require.Equal(t, []string{"conn 1"}, closed)
+	require.ErrorIs(t, ctn.Release(first), di.ErrDoesNotExist)
+
+	closed = nil
+	require.NoError(t, ctn.Release(session))
+	require.Equal(t, []string{"conn 3"}, closed)
+	require.ErrorIs(t, ctn.Release(session.Conn), di.ErrDoesNotExist)
+	require.ErrorIs(t, ctn.Release(pool), di.ErrDoesNotExist)
+	require.ErrorIs(t, ctn.Release(nil), di.ErrInvalidValue)
+
+	closed = nil
+	require.NoError(t, ctn.Call(func(s *Session, c *Conn) {
+		require.Equal(t, 4, s.Conn.ID)
+		require.Equal(t, 5, c.ID)
+		require.Empty(t, closed)
+	}, di.AutoRelease()))
+	require.Equal(t, []string{"conn 5", "conn 4"}, closed)
+
+	closed = nil
+	require.NoError(t, ctn.Call(func(*Conn) {}))
+	require.Empty(t, closed)
+
+	closed = nil
+	require.NoError(t, ctn.Close())
+	require.Equal(t, []string{"conn 6", "pool", "conn 2"}, closed)
+	require.ErrorIs(t, ctn.Release(second), di.ErrClosed)
+
+	t.Run("Value", func(t *testing.T) {
+		type Value struct{ ID int }
+
+		var closed = 0
+		builder, err = di.NewBuilder(
+			di.Provide(func() (Value, func() error) {
+				return Value{ID: 1}, func() error {
+					closed++
+					return nil
+				}
+			}, di.Unshared()),
+		)
+
+		require.NoError(t, err)
+
+		ctn, err = builder.Build()
+		require.NoError(t, err)
+
+		var first, second Value
+		require.NoError(t, ctn.Resolve(&first))
+		require.NoError(t, ctn.Resolve(&second))
+		require.Equal(t, first, second)
+
+		require.ErrorIs(t, ctn.Release(first), di.ErrInvalidValue)
+		require.Zero(t, closed)
+
+		require.NoError(t, ctn.Close())
+		require.Equal(t, 2, closed)
+	})
+
+	t.Run("Close during call", func(t *testing.T) {
+		builder, err = di.NewBuilder(
+			di.Provide(func() (*Conn, func() error) {
+				return &Conn{}, func() error {
+					closed = append(closed, "conn")
+					return nil
+				}
+			}, di.Unshared()),
+		)
+
+		require.NoError(t, err)
+
+		ctn, err = builder.Build()
+		require.NoError(t, err)
+
+		closed = nil
+		require.NoError(t, ctn.Call(func(*Conn) {
+			require.NoError(t, ctn.Close())
+		}, di.AutoRelease()))
+		require.Equal(t, []string{"conn"}, closed)
+	})
+}
+
+func TestContainer_OutputsAliases(t *testing.T) {
+	type (
+		Result struct {
+			di.Out
+
+			Bar *BarController
+			Baz *BazController `as:"Controller"`
+		}
+
+		Unknown struct {
+			di.Out
+
+			Bar *BarController `as:"Unknown"`
+		}
+
+		Unexported struct {
+			di.Out
+
+			bar *BarController
+		}
+	)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func() Result {
+			return Result{Bar: NewBarController(), Baz: NewBazController()}
+		}, di.As(new(Controller))),
+		di.Provide(func() (*ManualResolver, *CycledController) {
+			return &ManualResolver{}, &CycledController{}
+		}, di.As(new(Controller)), di.Tags{{Name: "outputs"}}),
+	)
+
+	require.NoError(t, err)
+	require.Len(t, builder.Definitions(), 6)
+
+	for _, def := range builder.Definitions() {
+		require.NotEqual(t, "compiler.Outputs", def.Type().String())
+		require.NotEqual(t, "di_test.Result", def.Type().String())
+	}
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+	require.Len(t, ctn.Definitions(), 4)
+	require.Equal(t, 4, ctn.Stats().Definitions)
+
+	var controllers []Controller
+	require.NoError(t, ctn.Resolve(&controllers))
+	require.Len(t, controllers, 2)
+	require.IsType(t, &BazController{}, controllers[0])
+	require.IsType(t, &CycledController{}, controllers[1])
+
+	_, err = di.NewBuilder(
+		di.Provide(func() Unknown { return Unknown{} }, di.As(new(Controller))),
+	)
+
+	require.ErrorIs(t, err, di.ErrDoesNotExist)
+
+	_, err = di.NewBuilder(
+		di.Provide(func() (*ManualResolver, *http.Server) { return nil, nil }, di.As(new(Controller))),
+	)
+
+	require.ErrorIs(t, err, di.ErrNotImplementInterface)
+
+	_, err = di.NewBuilder(
+		di.Provide(func() Unexported { return Unexported{bar: NewBarController()} }),
+	)
+
+	require.ErrorIs(t, err, di.ErrInvalidConstructor)
+	require.ErrorContains(t, err, "container_test.go:")
+}
+
+func TestContainer_OutputsPriority(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController, di.As(new(Controller))),
+		di.Provide(func() (*BazController, *http.Server) {
+			return NewBazController(), &http.Server{}
+		}, di.As(new(Controller)), di.Priority(10)),
+		di.Provide(func() (*CycledController, *http.ServeMux) {
+			return &CycledController{}, http.NewServeMux()
+		}, di.As(new(Controller)), di.Priority(20)),
+	)
+
+	require.NoError(t, err)
+
+	for _, def := range builder.Definitions() {
+		switch def.Type().String() {
+		case "*di_test.BazController", "*http.Server":
+			require.Equal(t, 10, def.Priority())
+		case "*di_test.CycledController", "*http.ServeMux":
+			require.Equal(t, 20, def.Priority())
+		}
+	}
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+	require.Equal(t, 5, ctn.Stats().Definitions)
+
+	var controllers []Controller
+	require.NoError(t, ctn.Resolve(&controllers, di.ByPriority()))
+	require.Len(t, controllers, 3)
+	require.IsType(t, &CycledController{}, controllers[0])
+	require.IsType(t, &BazController{}, controllers[1])
+	require.IsType(t, &BarController{}, controllers[2])
+}
+
+func TestContainer_LazyAfterConstruction(t *testing.T) {
+	type (
+		B struct{}
+		C struct{}
+		A struct {
+			Lazy     di.Lazy[*B]
+			Provider func() (*B, error)
+			Failing  func() (*C, error)
+		}
+	)
+
+	var builder, err = di.NewBuilder(
+		di.Provide(func(lazy di.Lazy[*B], provider func() (*B, error), failing func() (*C, error)) *A {
+			return &A{Lazy: lazy, Provider: provider, Failing: failing}
+		}, di.Unshared()),
+		di.Provide(func(*A) *B { return &B{} }),
+		di.Provide(func() (*C, error) { return nil, errors.New("failed") }),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var a *A
+	require.NoError(t, ctn.Resolve(&a))
+
+	var lazy, provided *B
+	lazy, err = a.Lazy.Get()
+	require.NoError(t, err)
+
+	provided, err = a.Provider()
+	require.NoError(t, err)
+	require.Same(t, lazy, provided)
+
+	_, err = a.Failing()
+	require.ErrorContains(t, err, "failed")
+	require.Regexp(t, `^.*container_test.go:\d+ : `, err.Error())
+}
+
+func TestContainer_StateOutputs(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(func() (*BarController, *BazController, func() error) {
+			time.Sleep(5 * time.Millisecond)
+			return NewBarController(), NewBazController(), func() error { return nil }
+		}),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	var bar *BarController
+	require.NoError(t, ctn.Resolve(&bar))
+
+	for _, def := range ctn.Definitions() {
+		var state = ctn.State(def)
+		if def.Type().String() == "*di_test.BazController" {
+			require.False(t, state.Instantiated)
+			continue
+		}
+
+		require.True(t, state.Instantiated)
+		require.True(t, state.Closer)
+		require.GreaterOrEqual(t, state.Duration, 5*time.Millisecond)
+	}
+}
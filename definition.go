@@ -6,6 +6,7 @@ package di
 
 import (
 	"reflect"
+	"sort"
 
 	"github.com/gozix/di/internal/compiler"
 	"github.com/gozix/di/internal/runtime"
@@ -16,9 +17,14 @@ type (
 	definition struct {
 		id          int
 		aliases     []any
+		assisted    []any
 		compiler    compiler.Compiler
 		constraints constraints
 		frame       runtime.Frame
+		key         string
+		name        string
+		priority    int
+		root        *definition
 		tags        Tags
 		unshared    bool
 
@@ -27,29 +33,38 @@ type (
 
 	// definitions are list of definitions.
 	definitions map[reflect.Type][]definition
+
+	// lifetime of the definition instances, the longer lifetime is greater.
+	lifetime int
+)
+
+const (
+	lifetimeUnshared lifetime = iota
+	lifetimeShared
 )
 
 // definition implements the Definition interface.
 var _ Definition = (*definition)(nil)
 
 func (d *definition) Dependencies() []Dependency {
+	if d.root != nil {
+		var root = *d.root
+		root.definitions = d.definitions
+
+		return root.Dependencies()
+	}
+
 	var deps []Dependency
 	for _, dep := range d.compiler.Dependencies() {
-		var (
-			constr = d.constraints.choose(dep.Index, dep.Name, dep.Type)
-			defs   = make([]Definition, 0, 2)
-		)
-
-		for _, def := range d.definitions.find(dep.Type, constr.modifiers) {
-			def.definitions = d.definitions
-			defs = append(defs, Definition(&def))
+		if !isIn(dep.Type) {
+			deps = append(deps, d.dependency(dep.Type, d.constraints.choose(dep.Index, dep.Name, dep.Type)))
+			continue
 		}
 
-		deps = append(deps, Dependency{
-			Type:        dep.Type,
-			Optional:    constr.optional,
-			Definitions: defs,
-		})
+		for _, field := range inFields(dep.Type) {
+			var constr, _ = inConstraint(field)
+			deps = append(deps, d.dependency(field.Type, constr))
+		}
 	}
 
 	return deps
@@ -59,6 +74,14 @@ func (d *definition) ID() int {
 	return d.id
 }
 
+func (d *definition) Name() string {
+	return d.name
+}
+
+func (d *definition) Priority() int {
+	return d.priority
+}
+
 func (d *definition) Type() reflect.Type {
 	return d.compiler.Type()
 }
@@ -74,6 +97,83 @@ func (d *definition) Unshared() bool {
 	return d.unshared
 }
 
+// lifetime returns effective lifetime of the definition instances.
+func (d *definition) lifetime() lifetime {
+	if d.unshared {
+		return lifetimeUnshared
+	}
+
+	return lifetimeShared
+}
+
+func (l lifetime) String() string {
+	if l == lifetimeUnshared {
+		return "unshared"
+	}
+
+	return "shared"
+}
+
+func (d *definition) dependency(typ reflect.Type, constr constraint) Dependency {
+	var (
+		defs = make([]Definition, 0, 2)
+		lt   = typ
+	)
+
+	if elem, ok := lazyElem(typ); ok {
+		lt = elem
+	}
+
+	if elem, ok := providerElem(typ); ok && len(d.definitions[typ]) == 0 {
+		lt = elem
+	}
+
+	var found, _, _ = d.definitions.lookup(lt, constr.modifiers)
+	for _, def := range found {
+		def.definitions = d.definitions
+		defs = append(defs, Definition(&def))
+	}
+
+	return Dependency{
+		Type:        typ,
+		Optional:    constr.optional,
+		Definitions: defs,
+	}
+}
+
+// aliased checks that the definition has the alias named by the as tag.
+func (d *definition) aliased(name string) bool {
+	for _, alias := range d.aliases {
+		if at := reflect.TypeOf(alias); at != nil && at.Kind() == reflect.Ptr && aliasNamed(at.Elem(), name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasAlias checks that the definition has the alias of the interface type.
+func (d *definition) hasAlias(at reflect.Type) bool {
+	for _, alias := range d.aliases {
+		if reflect.TypeOf(alias) == reflect.PointerTo(at) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// constrains checks that constraint key matches any dependency.
+func (d *definition) constrains(key any) bool {
+	for _, dep := range d.compiler.Dependencies() {
+		if key == dep.Index || key == dep.Name || key == dep.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (d *definition) applyAddOptions(options ...AddOption) {
 	for _, o := range options {
 		o.applyAddOption(d)
@@ -106,3 +206,100 @@ func (d definitions) find(typ reflect.Type, modifiers []Modifier) (founded []def
 
 	return founded
 }
+
+// lookup finds definitions of the type the same way the container resolves them: slice and map with string
+// keys types fall back to the definitions of their element type. Found definitions are returned along with
+// ErrMultipleDefinitions.
+func (d definitions) lookup(typ reflect.Type, modifiers []Modifier) (defs []definition, keyed bool, err error) {
+	if defs = d.find(typ, modifiers); len(defs) == 0 {
+		switch {
+		case typ.Kind() == reflect.Slice:
+			defs = d.find(typ.Elem(), modifiers)
+		case isKeyed(typ):
+			if defs, err = keys(d.find(typ.Elem(), append([]Modifier{KeyByName()}, modifiers...))); err != nil {
+				return nil, false, err
+			}
+
+			keyed = true
+		}
+
+		if len(defs) == 0 {
+			return nil, false, ErrDoesNotExist
+		}
+	}
+
+	if typ.Kind() != reflect.Slice && !keyed && len(defs) > 1 {
+		return defs, false, ErrMultipleDefinitions
+	}
+
+	return defs, keyed, nil
+}
+
+// without returns copy of the definitions without definitions with the ids.
+func (d definitions) without(ids map[int]bool) definitions {
+	var kept = make(definitions, len(d))
+	for typ, defs := range d {
+		var items = make([]definition, 0, len(defs))
+		for _, def := range defs {
+			if !ids[def.id] {
+				items = append(items, def)
+			}
+		}
+
+		if len(items) > 0 {
+			kept[typ] = items
+		}
+	}
+
+	return kept
+}
+
+// all returns unique definitions along with roots of the outputs ordered by registration.
+func (d definitions) all() []definition {
+	var (
+		defs = d.list()
+		seen = make(map[int]bool)
+	)
+
+	for i := range defs {
+		if root := defs[i].root; root != nil && !seen[root.id] {
+			seen[root.id] = true
+
+			var def = *root
+			def.definitions = d
+			defs = append(defs, def)
+		}
+	}
+
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].id < defs[j].id
+	})
+
+	return defs
+}
+
+// list returns unique definitions ordered by registration.
+func (d definitions) list() []definition {
+	var (
+		defs = make([]definition, 0, len(d))
+		seen = make(map[int]bool, len(d))
+	)
+
+	for _, items := range d {
+		for _, def := range items {
+			if seen[def.id] {
+				continue
+			}
+
+			seen[def.id] = true
+			def.definitions = d
+			defs = append(defs, def)
+		}
+	}
+
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].id < defs[j].id
+	})
+
+	return defs
+}
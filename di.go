@@ -7,6 +7,7 @@ package di
 import (
 	"errors"
 	"reflect"
+	"time"
 
 	"github.com/gozix/di/internal/compiler"
 )
@@ -23,8 +24,10 @@ type (
 		//   - *http.Server{}
 		//   - etc.
 		// The options argument may be one of:
-		//   - di.Tags{}
 		//   - di.As()
+		//   - di.Name()
+		//   - di.Priority()
+		//   - di.Tags{}
 		Add(value Value, options ...AddOption) error
 
 		// Apply applies options to Builder.
@@ -32,7 +35,13 @@ type (
 		//   - di.BuilderOptions()
 		//   - di.Add()
 		//   - di.Autowire()
+		//   - di.DefaultOrder()
+		//   - di.Factory()
+		//   - di.Parallel()
 		//   - di.Provide()
+		//   - di.ProvideMethods()
+		//   - di.Replace()
+		//   - di.Strict()
 		Apply(options ...BuilderOption) error
 
 		// Autowire providers autowired type.
@@ -45,10 +54,47 @@ type (
 		// The options argument may be one of:
 		//   - di.As()
 		//   - di.Constraint()
+		//   - di.Name()
+		//   - di.Priority()
 		//   - di.Tags{}
 		//   - di.Unshared()
 		Autowire(target Type, options ...ProvideOption) error
 
+		// Factory provides factory function of the constructor.
+		//
+		// The constructor argument must be a function with the signature allowed by Provide, its parameters
+		// marked by di.Assisted() are passed to the factory function at runtime, the rest are resolved from
+		// the container. The factory function has the assisted parameters in the constructor order and
+		// the same results as the constructor, for example, constructor:
+		//   - func NewSession(db *sql.DB, userID string) (*Session, error)
+		// provided with di.Assisted(1) is resolved by the type:
+		//   - func(userID string) (*Session, error)
+		// Closers returned by the factory function are not managed by the container.
+		// The options argument may be one of:
+		//   - di.As()
+		//   - di.Assisted()
+		//   - di.Constraint()
+		//   - di.Name()
+		//   - di.Priority()
+		//   - di.Tags{}
+		//   - di.Unshared()
+		Factory(constructor Constructor, options ...ProvideOption) error
+
+		// Override atomically replaces definitions of the target type by the constructor.
+		//
+		// The target argument must contain the replaced type, for example:
+		//   - (*sql.DB)(nil)
+		//   - new(io.Writer)
+		//   - etc.
+		// All definitions of the target type are replaced, di.Replacing() restricts them by modifiers.
+		// The replaced definitions are removed from all types, the replacement gets the target interface
+		// type and aliases of the replaced definitions it implements. If the single definition is replaced,
+		// the replacement also gets its name and tags unless they are given by options. If the target type was never provided,
+		// or the replacement can not be provided, the definitions stay untouched. A nil constructor
+		// is reported as ErrIsNil.
+		// The constructor and options arguments are the same as in Provide, plus di.Replacing().
+		Override(target Type, constructor Constructor, options ...ProvideOption) error
+
 		// Provide provides any constructor.
 		//
 		// The constructor argument must be a function with one of the following signatures:
@@ -56,14 +102,40 @@ type (
 		//   - func New(constraints ...any) (value any, err error)
 		//   - func New(constraints ...any) (value any, closer func(){})
 		//   - func New(constraints ...any) (value any, closer func(){}, err error)
+		//
+		// Any argument may be a struct that embeds di.In, then its fields are resolved individually.
+		// Any of them may return several values before closer and error, or a struct that embeds di.Out.
+		// Every value or exported field becomes a separate definition, all of them share one constructor call.
+		// The aliases go only to the values implementing them, the name and tags go to all of them.
 		// The options argument may be one of:
 		//   - di.As()
 		//   - di.Constraint()
+		//   - di.Fields{}
+		//   - di.Name()
+		//   - di.Priority()
 		//   - di.Tags{}
 		//   - di.Unshared()
 		Provide(constructor Constructor, options ...ProvideOption) error
 
+		// ProvideMethods provides every exported method of value that has a valid constructor signature.
+		//
+		// Each method is provided as its own definition with the receiver bound. The value argument
+		// should usually be a pointer, because methods with a pointer receiver are not a part of
+		// the method set of a struct value.
+		// The options argument may be one of:
+		//   - di.As()
+		//   - di.Constraint()
+		//   - di.Methods{}
+		//   - di.Name()
+		//   - di.Priority()
+		//   - di.Tags{}
+		//   - di.Unshared()
+		ProvideMethods(value Value, options ...ProvideOption) error
+
 		// Build is container build method.
+		//
+		// In strict mode Build validates definitions and returns *ValidationError with all violations. Otherwise,
+		// captive dependencies are reported to the handler set by di.Warnings.
 		Build() (Container, error)
 
 		// Definitions are build snapshot of definitions.
@@ -79,9 +151,12 @@ type (
 		// Call calls the function with resolved arguments.
 		//
 		// The fn argument must contain any function. If the function contains error in the last return type,
-		// then Call will return that value as own return type value.
+		// then Call will return that value as own return type value. Any argument may be a struct that embeds di.In,
+		// then its fields are resolved individually.
 		// The options argument may be one of:
+		//   - di.AutoRelease()
 		//   - di.Constraint()
+		//   - di.Parallel()
 		Call(fn Function, options ...ConstraintOption) (err error)
 
 		// Close runs closers in reverse order that has been created.
@@ -89,23 +164,89 @@ type (
 		// Any close function can return any error that stop the calling loop for all rest closers. Any close function
 		// can return any error that stop the calling loop for all rest closers. That error will return in function
 		// return type.
+		//
+		// Close waits for in-flight resolutions, so it must not be called by constructors. After Close, Resolve
+		// and Call return ErrClosed and Has returns false. Repeated calls wait for the first one and return
+		// its result.
 		Close() error
 
+		// Done returns a channel that is closed when Close has completed.
+		Done() <-chan struct{}
+
+		// Definitions are snapshot of the container definitions ordered by registration.
+		Definitions() []Definition
+
+		// Count returns the number of definitions that Resolve would choose for the type.
+		//
+		// If Resolve fails with ErrMultipleDefinitions, Count returns the number of the conflicting definitions,
+		// while Has returns false. The value and modifiers arguments are the same as in Has.
+		Count(value Type, modifiers ...Modifier) int
+
+		// Explain describes which definitions Resolve would choose for the type and why.
+		//
+		// The value and modifiers arguments are the same as in Has.
+		Explain(value Type, modifiers ...Modifier) string
+
+		// Invalidate closes and evicts cached instances of the type and, transitively, all cached instances
+		// depending on them, so the next resolution constructs them again.
+		//
+		// The value and modifiers arguments are the same as in Has. All closers of the evicted instances are run,
+		// the first error is returned. Unshared instances are never closed, use Release for them.
+		Invalidate(value Type, modifiers ...Modifier) error
+
+		// Refresh recreates cached instances of the type and, transitively, all cached instances depending on them.
+		//
+		// The new instances replace the old ones only if all of them are created, then the old instances are closed.
+		// Otherwise the new instances, including unshared ones created for them, are closed, the old ones are kept
+		// and the error is returned.
+		// The value and modifiers arguments are the same as in Has.
+		Refresh(value Type, modifiers ...Modifier) error
+
+		// Release runs and removes closers of the unshared instance and of the unshared instances created
+		// for it, so they are not kept until Close.
+		//
+		// The value argument must be the instance returned by the container. Only non-nil pointers and channels
+		// identify their instances, for values of other kinds ErrInvalidValue is returned and such instances
+		// are kept until Close. If the instance has no closers or has been already released, ErrDoesNotExist
+		// is returned.
+		Release(value Value) error
+
 		// Has checks that type exists in container, if not it return false.
 		//
-		// The value argument must contain the wanted type, for example:
+		// Has returns true only if Resolve would choose definitions for the type, ambiguous types
+		// failing with ErrMultipleDefinitions are reported as missing.
+		// The type is looked up the same way as Resolve does, if the pointer type can not be resolved,
+		// its element type is checked. The value argument must contain the wanted type, for example:
 		//   - (*http.Server)(nil)
 		//   - (*io.Writer)(nil)
 		//   - new(io.Writer)
 		//   - etc.
 		// The modifiers argument may be one of:
+		//   - di.ByPriority()
+		//   - di.Named()
+		//   - di.WithTagArg()
+		//   - di.WithTagArgMatch()
 		//   - di.WithTags()
 		Has(value Type, modifiers ...Modifier) (exist bool)
 
+		// Stats returns snapshot of the container statistics.
+		Stats() Stats
+
+		// State returns runtime state of the definition.
+		State(def Definition) State
+
 		// Resolve resolves type and fills target pointer.
 		//
-		// The target argument must contain reference to wanted variable.
+		// The target argument must contain reference to wanted variable. A reference to map with string keys
+		// is filled with definitions of the map value type keyed by definition name or the provided key.
 		// The modifiers argument may be one of:
+		//   - di.ByPriority()
+		//   - di.KeyBy()
+		//   - di.KeyByName()
+		//   - di.KeyByTagArg()
+		//   - di.Named()
+		//   - di.WithTagArg()
+		//   - di.WithTagArgMatch()
 		//   - di.WithTags()
 		Resolve(target Value, modifiers ...Modifier) (err error)
 	}
@@ -118,6 +259,12 @@ type (
 		// Dependencies is definition type dependencies getter.
 		Dependencies() []Dependency
 
+		// Name is definition name getter.
+		Name() string
+
+		// Priority is definition priority getter.
+		Priority() int
+
 		// Tags is definition tags getter.
 		Tags() Tags
 
@@ -140,6 +287,57 @@ type (
 		Definitions []Definition
 	}
 
+	// State represent runtime state of the container definition.
+	State struct {
+		// Instantiated is true if at least one instance was created.
+		Instantiated bool
+
+		// Instances is number of created instances, unshared definitions may have several.
+		Instances int
+
+		// CreatedAt is start time of the last instance creation.
+		CreatedAt time.Time
+
+		// Duration is duration of the last instance creation, without its dependencies.
+		Duration time.Duration
+
+		// Closer is true if the closer of any instance is registered.
+		//
+		// CreatedAt, Duration and Closer of the constructor output, a result of the constructor with several
+		// results or a field of di.Out, are ones of the constructor call shared by all its outputs.
+		Closer bool
+
+		// Dependents are definitions which instances depend on the definition.
+		Dependents []Definition
+	}
+
+	// Stats represent container statistics.
+	Stats struct {
+		// Definitions is number of the container definitions.
+		Definitions int `json:"definitions"`
+
+		// Instances is number of created instances.
+		Instances uint64 `json:"instances"`
+
+		// CacheHits is number of shared instances taken from the cache.
+		CacheHits uint64 `json:"cache_hits"`
+
+		// CacheMisses is number of shared instances missed in the cache.
+		CacheMisses uint64 `json:"cache_misses"`
+
+		// Failures is number of failed constructions.
+		Failures uint64 `json:"failures"`
+
+		// ClosersRegistered is number of registered closers.
+		ClosersRegistered uint64 `json:"closers_registered"`
+
+		// ClosersRun is number of run closers.
+		ClosersRun uint64 `json:"closers_run"`
+
+		// ConstructionTime is cumulative construction time of all instances.
+		ConstructionTime time.Duration `json:"construction_time"`
+	}
+
 	// Function is any function.
 	Function any
 
@@ -172,9 +370,31 @@ var (
 	// ErrorMustBeFunction triggered when value not a function.
 	ErrorMustBeFunction = errors.New("must be a function")
 
+	// ErrClosed is error triggered when the container is used after Close.
+	ErrClosed = errors.New("container closed")
+
 	// ErrCycleDetected is error triggered when was cycle detected.
 	ErrCycleDetected = errors.New("cycle detected")
 
+	// ErrDuplicateName is error triggered when definitions of the same type have the same name.
+	ErrDuplicateName = errors.New("duplicate name")
+
+	// ErrDuplicateKey is error triggered when definitions resolved in map have the same key.
+	ErrDuplicateKey = errors.New("duplicate key")
+
+	// ErrContainerInjection is error triggered in strict mode when definition depends on the container.
+	ErrContainerInjection = errors.New("container injection")
+
+	// ErrCaptiveDependency is error triggered by the validation when definition depends on the definition
+	// living shorter than itself.
+	ErrCaptiveDependency = errors.New("captive dependency")
+
+	// ErrUnsharedCloser is error triggered in strict mode when unshared definition returns closer.
+	ErrUnsharedCloser = errors.New("unshared closer")
+
+	// ErrNoProviderMethods is error triggered when value has no methods with a valid constructor signature.
+	ErrNoProviderMethods = errors.New("no provider methods")
+
 	// ErrInvalidConstructor is error triggered when constructor have invalid signature.
 	ErrInvalidConstructor = compiler.ErrInvalidConstructor
 
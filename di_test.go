@@ -41,6 +41,10 @@ type (
 		bar *BarController
 		baz *BazController
 	}
+
+	Providers struct {
+		Addr string
+	}
 )
 
 func (c *BarController) Register(srv *http.ServeMux) {
@@ -124,3 +128,16 @@ func NewManualResolver(ctn di.Container, bar *BarController) (*ManualResolver, e
 		baz: baz,
 	}, nil
 }
+
+func (p *Providers) NewServer(mux *http.ServeMux) *http.Server {
+	return &http.Server{
+		Addr:    p.Addr,
+		Handler: mux,
+	}
+}
+
+func (p *Providers) NewServerMux() *http.ServeMux {
+	return http.NewServeMux()
+}
+
+func (p *Providers) Register(_ *http.ServeMux) {}
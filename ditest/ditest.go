@@ -0,0 +1,148 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+// Package ditest provides helpers for testing containers.
+package ditest
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/gozix/di"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Closer is a spy closer that records calls, the zero value is ready to use.
+type Closer struct {
+	// Err is returned by every Close call.
+	Err error
+
+	mux   sync.Mutex
+	calls int
+}
+
+// New builds the container from options and closes it when the test and all its subtests complete.
+func New(t testing.TB, options ...di.BuilderOption) di.Container {
+	t.Helper()
+
+	var builder, err = di.NewBuilder(options...)
+	require.NoError(t, err)
+
+	return build(t, builder)
+}
+
+// Override substitutes all definitions of the target type by the fake value.
+//
+// The target argument is the same as in di.Replace, the fake must be assignable to the target type.
+// Errors point at the caller of Override, a nil fake is reported as di.ErrIsNil.
+func Override(target di.Type, fake di.Value, options ...di.ProvideOption) di.BuilderOption {
+	options = append([]di.ProvideOption{di.Caller(0)}, options...)
+	if fake == nil {
+		return di.Replace(target, nil, options...)
+	}
+
+	var (
+		rv = reflect.ValueOf(fake)
+		fn = reflect.MakeFunc(
+			reflect.FuncOf(nil, []reflect.Type{rv.Type()}, false),
+			func([]reflect.Value) []reflect.Value {
+				return []reflect.Value{rv}
+			},
+		)
+	)
+
+	return di.Replace(target, fn.Interface(), options...)
+}
+
+// ResolveAll builds the container and resolves every definition of the builder, all failures are reported
+// to the test. The container is closed when the test and all its subtests complete.
+func ResolveAll(t testing.TB, builder di.Builder) di.Container {
+	t.Helper()
+
+	var (
+		ctn  = build(t, builder)
+		defs = builder.Definitions()
+		seen = make(map[int]bool, len(defs))
+	)
+
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].ID() < defs[j].ID()
+	})
+
+	for _, def := range defs {
+		if seen[def.ID()] {
+			continue
+		}
+
+		seen[def.ID()] = true
+
+		var (
+			id     = def.ID()
+			target = reflect.New(def.Type())
+			err    = ctn.Resolve(target.Interface(), di.Filter(func(def di.Definition) bool {
+				return def.ID() == id
+			}))
+		)
+
+		if err != nil {
+			t.Errorf("unable to resolve definition %d of type %s : %s", id, def.Type(), err)
+		}
+	}
+
+	return ctn
+}
+
+// RequireResolvable resolves the type parameter from the container and fails the test on error.
+func RequireResolvable[T any](t testing.TB, ctn di.Container, modifiers ...di.Modifier) T {
+	t.Helper()
+
+	var value T
+	require.NoError(t, ctn.Resolve(&value, modifiers...))
+
+	return value
+}
+
+// RequireCloserCalled asserts that the closer was called exactly once.
+func RequireCloserCalled(t testing.TB, closer *Closer) {
+	t.Helper()
+
+	require.Equal(t, 1, closer.Calls(), "closer must be called exactly once")
+}
+
+// Close records the call and returns Err.
+func (c *Closer) Close() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.calls++
+
+	return c.Err
+}
+
+// Calls returns the number of Close calls.
+func (c *Closer) Calls() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return c.calls
+}
+
+// build builds the container and registers its Close in the test cleanup.
+func build(t testing.TB, builder di.Builder) di.Container {
+	t.Helper()
+
+	var ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		if err := ctn.Close(); err != nil {
+			t.Errorf("unable to close container : %s", err)
+		}
+	})
+
+	return ctn
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package ditest_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gozix/di"
+	"github.com/gozix/di/ditest"
+
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	Store interface {
+		Get(key string) string
+	}
+
+	FakeStore struct{}
+
+	Service struct {
+		Store Store
+	}
+
+	// recorder is testing.TB that records errors instead of failing the test.
+	recorder struct {
+		testing.TB
+		errors []string
+	}
+)
+
+func (FakeStore) Get(key string) string {
+	return "fake " + key
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func NewService(store Store) *Service {
+	return &Service{Store: store}
+}
+
+func TestNew(t *testing.T) {
+	var closer = &ditest.Closer{}
+
+	t.Run("New", func(t *testing.T) {
+		var ctn = ditest.New(t,
+			di.Provide(func() (*Service, func() error) {
+				return &Service{}, closer.Close
+			}),
+		)
+
+		ditest.RequireResolvable[*Service](t, ctn)
+		require.Zero(t, closer.Calls())
+	})
+
+	ditest.RequireCloserCalled(t, closer)
+}
+
+func TestOverride(t *testing.T) {
+	var ctn = ditest.New(t,
+		di.Provide(func() Store { return nil }),
+		di.Provide(NewService),
+		ditest.Override(new(Store), FakeStore{}),
+	)
+
+	var service = ditest.RequireResolvable[*Service](t, ctn)
+	require.Equal(t, "fake key", service.Store.Get("key"))
+
+	t.Run("Named", func(t *testing.T) {
+		var ctn = ditest.New(t,
+			di.Provide(func() Store { return nil }, di.Name("primary")),
+			di.Provide(NewService, di.Constraint(0, di.Named("primary"))),
+			ditest.Override(new(Store), FakeStore{}),
+		)
+
+		var service = ditest.RequireResolvable[*Service](t, ctn)
+		require.Equal(t, "fake key", service.Store.Get("key"))
+	})
+
+	t.Run("Nil fake", func(t *testing.T) {
+		var _, err = di.NewBuilder(
+			di.Provide(func() Store { return nil }),
+			ditest.Override(new(Store), nil),
+		)
+
+		require.ErrorIs(t, err, di.ErrIsNil)
+		require.ErrorContains(t, err, "ditest_test.go:")
+	})
+
+	t.Run("Caller frame", func(t *testing.T) {
+		var _, err = di.NewBuilder(
+			ditest.Override(new(Store), FakeStore{}),
+		)
+
+		require.ErrorIs(t, err, di.ErrDoesNotExist)
+		require.ErrorContains(t, err, "ditest_test.go:")
+	})
+}
+
+func TestResolveAll(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(NewService),
+		di.Provide(func() (Store, error) { return nil, errors.New("store is broken") }),
+		di.Provide(func() (*FakeStore, error) { return nil, errors.New("fake is broken") }),
+		di.Add(FakeStore{}),
+	)
+	require.NoError(t, err)
+
+	var rec = &recorder{TB: t}
+	ditest.ResolveAll(rec, builder)
+
+	require.Len(t, rec.errors, 3)
+	require.Contains(t, rec.errors[0], "*ditest_test.Service")
+	require.Contains(t, rec.errors[1], "ditest_test.Store")
+	require.Contains(t, rec.errors[2], "*ditest_test.FakeStore")
+}
+
+func TestCloser(t *testing.T) {
+	var closer = &ditest.Closer{Err: errors.New("close")}
+	require.EqualError(t, closer.Close(), "close")
+	ditest.RequireCloserCalled(t, closer)
+}
@@ -5,15 +5,24 @@
 package di
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
-// TypeError records an error and type that caused it.
-type TypeError struct {
-	Type reflect.Type
-	Err  error
-}
+type (
+	// TypeError records an error and type that caused it.
+	TypeError struct {
+		Type reflect.Type
+		Err  error
+	}
+
+	// ValidationError records all violations found by the builder validation.
+	ValidationError struct {
+		Errors []error
+	}
+)
 
 // NewTypeError is error constructor.
 func NewTypeError(typ reflect.Type, err error) error {
@@ -34,3 +43,26 @@ func (e *TypeError) Error() string {
 func (e *TypeError) Unwrap() error {
 	return e.Err
 }
+
+func (e *ValidationError) Error() string {
+	var messages = make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("validation failed : %s", strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.Errors
+}
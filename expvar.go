@@ -0,0 +1,17 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import "expvar"
+
+// PublishExpvar publishes the container statistics as the expvar variable with the name.
+//
+// The statistics are taken on every read, so /debug/vars always shows the actual values. Like expvar.Publish,
+// it panics if the name is already registered.
+func PublishExpvar(name string, ctn Container) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return ctn.Stats()
+	}))
+}
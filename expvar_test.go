@@ -0,0 +1,39 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/gozix/di"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	var builder, err = di.NewBuilder(
+		di.Provide(NewBarController),
+	)
+
+	require.NoError(t, err)
+
+	var ctn di.Container
+	ctn, err = builder.Build()
+	require.NoError(t, err)
+
+	di.PublishExpvar("di_test", ctn)
+	require.NoError(t, ctn.Resolve(new(*BarController)))
+
+	var stats di.Stats
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get("di_test").String()), &stats))
+	require.Equal(t, 1, stats.Definitions)
+	require.Equal(t, uint64(1), stats.Instances)
+
+	require.Panics(t, func() {
+		di.PublishExpvar("di_test", ctn)
+	})
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gozix/di/internal/compiler"
+)
+
+// In is a marker of the parameter object.
+//
+// Every exported field of the struct that embeds In is resolved individually, so constructors and functions
+// passed to Container.Call may accept a single struct instead of a long list of parameters.
+//
+//	type Params struct {
+//		di.In
+//
+//		Logger      *Logger
+//		Tracer      *Tracer      `optional:"true"`
+//		Primary     *sql.DB      `name:"primary"`
+//		Controllers []Controller `group:"controller"`
+//	}
+//
+// The fields may be restricted with struct tags:
+//   - optional:"true" makes the field optional;
+//   - name:"primary" resolves only the definition with name primary;
+//   - tags:"a,b" resolves only definitions tagged with a and b;
+//   - group:"name" resolves slice of all definitions tagged with name, the group may be empty.
+type In struct{}
+
+// reflectInType is In reflect type cache.
+var reflectInType = reflect.TypeOf(In{})
+
+// isIn checks that type is a struct which embeds In.
+func isIn(rt reflect.Type) bool {
+	if rt.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < rt.NumField(); i++ {
+		if rt.Field(i).Anonymous && rt.Field(i).Type == reflectInType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inFields returns resolvable fields of the parameter object.
+func inFields(rt reflect.Type) []reflect.StructField {
+	var fields = make([]reflect.StructField, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		var field = rt.Field(i)
+		if !field.IsExported() || field.Anonymous && field.Type == reflectInType {
+			continue
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// inConstraint parses struct tags of the parameter object field.
+func inConstraint(field reflect.StructField) (c constraint, err error) {
+	if value, ok := field.Tag.Lookup("optional"); ok {
+		if c.optional, err = strconv.ParseBool(value); err != nil {
+			return c, fmt.Errorf("field %s : optional tag : %w", field.Name, err)
+		}
+	}
+
+	if value, ok := field.Tag.Lookup("name"); ok {
+		c.modifiers = append(c.modifiers, Named(value))
+	}
+
+	if value := field.Tag.Get("tags"); value != "" {
+		var tags = make([]string, 0, 2)
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				tags = append(tags, name)
+			}
+		}
+
+		c.modifiers = append(c.modifiers, WithTags(tags...))
+	}
+
+	if value := field.Tag.Get("group"); value != "" {
+		if field.Type.Kind() != reflect.Slice {
+			return c, fmt.Errorf("field %s : group tag : %w", field.Name, NewTypeError(field.Type, ErrMustBeSliceOrPointer))
+		}
+
+		c.optional = true
+		c.modifiers = append(c.modifiers, WithTags(value))
+	}
+
+	return c, nil
+}
+
+// validateDependencies checks struct tags of the parameter objects.
+func validateDependencies(cmp compiler.Compiler) error {
+	for _, dep := range cmp.Dependencies() {
+		if !isIn(dep.Type) {
+			continue
+		}
+
+		for _, field := range inFields(dep.Type) {
+			if _, err := inConstraint(field); err != nil {
+				return NewTypeError(dep.Type, err)
+			}
+		}
+	}
+
+	return nil
+}
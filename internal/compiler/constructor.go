@@ -10,14 +10,20 @@ import (
 	"reflect"
 )
 
-// Constructor implements the Compiler interface.
-type Constructor struct {
-	typ reflect.Type
-	val reflect.Value
-	vct bool
-	lin int
-	beh int
-}
+type (
+	// Constructor implements the Compiler interface.
+	Constructor struct {
+		typ reflect.Type
+		val reflect.Value
+		vct bool
+		lin int
+		num int
+		beh int
+	}
+
+	// Outputs are values created by the constructor with multiple outputs.
+	Outputs []reflect.Value
+)
 
 const (
 	behaviourUnknown = iota
@@ -34,6 +40,9 @@ var (
 	// reflectErrorType is error reflect type cache.
 	reflectErrorType = reflect.TypeOf((*error)(nil)).Elem()
 
+	// reflectOutputsType is Outputs reflect type cache.
+	reflectOutputsType = reflect.TypeOf(Outputs(nil))
+
 	// ErrInvalidConstructor is error triggered when constructor have invalid signature.
 	ErrInvalidConstructor = errors.New("unexpected constructor")
 )
@@ -44,6 +53,8 @@ var (
 //   - func New(args ...any) (value any, err error)
 //   - func New(args ...any) (value any, closer func(){})
 //   - func New(args ...any) (value any, closer func(){}, err error)
+//
+// Any of them may return several values before closer and error, in that case the constructor type is Outputs.
 func NewConstructor(fn any) (*Constructor, error) {
 	var c = &Constructor{
 		typ: reflect.TypeOf(fn),
@@ -76,19 +87,24 @@ func (c *Constructor) Create(dependencies ...*Dependency) (_ reflect.Value, _ Cl
 	}
 
 	var out []reflect.Value
-	if out, err = c.call(args); err != nil {
+	if out, err = c.call(args, false); err != nil {
 		return reflect.Value{}, nil, err
 	}
 
+	var value = out[0]
+	if c.num > 1 {
+		value = reflect.ValueOf(Outputs(out[:c.num]))
+	}
+
 	switch c.beh {
 	case behaviourValue:
-		return out[0], nil, nil
+		return value, nil, nil
 	case behaviourValueError:
-		return out[0], nil, c.nilOrError(out[1])
+		return value, nil, c.nilOrError(out[c.num])
 	case behaviourValueCloser:
-		return out[0], c.nilOrCloser(out[1]), nil
+		return value, c.nilOrCloser(out[c.num]), nil
 	case behaviourValueCloserError:
-		return out[0], c.nilOrCloser(out[1]), c.nilOrError(out[2])
+		return value, c.nilOrCloser(out[c.num]), c.nilOrError(out[c.num+1])
 	}
 
 	return reflect.Value{}, nil, ErrInvalidConstructor
@@ -108,34 +124,81 @@ func (c *Constructor) Dependencies() []*Dependency {
 	return deps
 }
 
+// Closer returns true if the constructor returns closer.
+func (c *Constructor) Closer() bool {
+	return c.beh == behaviourValueCloser || c.beh == behaviourValueCloserError
+}
+
+// Outputs returns types of all values created by the constructor.
+func (c *Constructor) Outputs() []reflect.Type {
+	var types = make([]reflect.Type, c.num)
+	for i := range types {
+		types[i] = c.typ.Out(i)
+	}
+
+	return types
+}
+
 func (c *Constructor) Type() reflect.Type {
+	if c.num > 1 {
+		return reflectOutputsType
+	}
+
 	return c.typ.Out(0)
 }
 
-func (c *Constructor) call(args []reflect.Value) (_ []reflect.Value, err error) {
+func (c *Constructor) call(args []reflect.Value, slice bool) (_ []reflect.Value, err error) {
 	defer func() {
 		if recovered := recover(); recovered != nil {
 			err = fmt.Errorf("unable to create because the constructor %w : %+v", ErrPanicked, recovered)
 		}
 	}()
 
+	if slice {
+		return c.val.CallSlice(args), nil
+	}
+
 	return c.val.Call(args), nil
 }
 
 func (c *Constructor) guessBehaviour() {
-	switch {
-	case c.typ == nil:
+	if c.typ == nil || c.typ.Kind() != reflect.Func {
 		c.beh = behaviourUnknown
-	case c.typ.Kind() != reflect.Func:
+		return
+	}
+
+	var (
+		num    = c.typ.NumOut()
+		closer = false
+		failer = false
+	)
+
+	if num > 1 && c.isError(c.typ.Out(num-1)) {
+		failer = true
+		num--
+	}
+
+	if num > 1 && c.isCloser(c.typ.Out(num-1)) {
+		closer = true
+		num--
+	}
+
+	if num == 0 {
 		c.beh = behaviourUnknown
-	case c.typ.NumOut() == 1:
-		c.beh = behaviourValue
-	case c.typ.NumOut() == 2 && c.isError(c.typ.Out(1)):
-		c.beh = behaviourValueError
-	case c.typ.NumOut() == 2 && c.isCloser(c.typ.Out(1)):
-		c.beh = behaviourValueCloser
-	case c.typ.NumOut() == 3 && c.isCloser(c.typ.Out(1)) && c.isError(c.typ.Out(2)):
+		return
+	}
+
+	c.num = num
+
+	switch {
+	case closer && failer:
 		c.beh = behaviourValueCloserError
+	case closer:
+		c.beh = behaviourValueCloser
+	case failer:
+		c.beh = behaviourValueError
+	default:
+		c.beh = behaviourValue
 	}
 }
 
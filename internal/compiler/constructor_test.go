@@ -46,10 +46,8 @@ func TestConstructor(t *testing.T) {
 		Constructor: func() {},
 		Error:       compiler.ErrInvalidConstructor,
 	}, {
-		Constructor: func() (int, int, int, int) {
-			return 0, 0, 0, 0
-		},
-		Error: compiler.ErrInvalidConstructor,
+		Constructor: nil,
+		Error:       compiler.ErrInvalidConstructor,
 	}, {
 		Constructor: func() int {
 			panic("oops")
@@ -184,3 +182,27 @@ func TestConstructor(t *testing.T) {
 		})
 	}
 }
+
+func TestConstructor_Outputs(t *testing.T) {
+	var closer = func() error {
+		return nil
+	}
+
+	var cmp, err = compiler.NewConstructor(func() (int, string, func() error, error) {
+		return 1, "1", closer, nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, reflect.TypeOf(compiler.Outputs{}), cmp.Type())
+	require.Equal(t, []reflect.Type{reflect.TypeOf(0), reflect.TypeOf("")}, cmp.Outputs())
+	require.True(t, cmp.Closer())
+
+	var v, c, e = cmp.Create()
+	require.NoError(t, e)
+	require.Equal(t, reflect.ValueOf(closer).Pointer(), reflect.ValueOf(c).Pointer())
+
+	var outputs = v.Interface().(compiler.Outputs)
+	require.Len(t, outputs, 2)
+	require.Equal(t, 1, outputs[0].Interface())
+	require.Equal(t, "1", outputs[1].Interface())
+}
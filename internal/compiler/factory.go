@@ -0,0 +1,124 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package compiler
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Factory implements the Compiler interface.
+type Factory struct {
+	ctor     *Constructor
+	typ      reflect.Type
+	assisted []bool
+}
+
+// Factory implements the Compiler interface.
+var _ Compiler = (*Factory)(nil)
+
+// NewFactory is constructor of Factory.
+//
+// Argument fn must be a valid constructor, the assisted argument contains indexes of constructor parameters
+// which are passed to the factory function at runtime. The factory function has assisted parameters in the
+// constructor order and the same results as the constructor. The panic of the constructor is returned as
+// ErrPanicked by the error result of the factory function, the function without error result panics with it.
+func NewFactory(fn any, assisted []int) (*Factory, error) {
+	var ctor, err = NewConstructor(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	var f = &Factory{
+		ctor:     ctor,
+		assisted: make([]bool, ctor.typ.NumIn()),
+	}
+
+	for _, i := range assisted {
+		if i < 0 || i >= len(f.assisted) {
+			return nil, fmt.Errorf("got parameter index %d of %v : %w", i, ctor.typ, ErrInvalidConstructor)
+		}
+
+		f.assisted[i] = true
+	}
+
+	var (
+		in  = make([]reflect.Type, 0, len(assisted))
+		out = make([]reflect.Type, 0, ctor.typ.NumOut())
+	)
+
+	for i := 0; i < ctor.typ.NumIn(); i++ {
+		if f.assisted[i] {
+			in = append(in, ctor.typ.In(i))
+		}
+	}
+
+	for i := 0; i < ctor.typ.NumOut(); i++ {
+		out = append(out, ctor.typ.Out(i))
+	}
+
+	f.typ = reflect.FuncOf(in, out, ctor.vct && f.assisted[ctor.lin])
+
+	return f, nil
+}
+
+func (c *Factory) Create(dependencies ...*Dependency) (reflect.Value, Closer, error) {
+	var resolved = make([]reflect.Value, 0, len(dependencies))
+	for _, dep := range dependencies {
+		resolved = append(resolved, dep.Value)
+	}
+
+	return reflect.MakeFunc(c.typ, func(in []reflect.Value) []reflect.Value {
+		var (
+			args = make([]reflect.Value, 0, len(c.assisted))
+			ri   = 0
+			ai   = 0
+		)
+
+		for _, assisted := range c.assisted {
+			if assisted {
+				args = append(args, in[ai])
+				ai++
+
+				continue
+			}
+
+			args = append(args, resolved[ri])
+			ri++
+		}
+
+		var out, err = c.ctor.call(args, c.ctor.vct)
+		if err == nil {
+			return out
+		}
+
+		var num = c.typ.NumOut()
+		if num == 0 || c.typ.Out(num-1) != reflectErrorType {
+			panic(err)
+		}
+
+		out = make([]reflect.Value, 0, num)
+		for i := 0; i < num-1; i++ {
+			out = append(out, reflect.Zero(c.typ.Out(i)))
+		}
+
+		return append(out, reflect.ValueOf(&err).Elem())
+	}), nil, nil
+}
+
+func (c *Factory) Dependencies() []*Dependency {
+	var deps = make([]*Dependency, 0, len(c.assisted))
+	for _, dep := range c.ctor.Dependencies() {
+		if !c.assisted[dep.Index] {
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps
+}
+
+func (c *Factory) Type() reflect.Type {
+	return c.typ
+}
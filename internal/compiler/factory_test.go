@@ -0,0 +1,137 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package compiler_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gozix/di/internal/compiler"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactory(t *testing.T) {
+	type TestCase struct {
+		Constructor  any
+		Assisted     []int
+		Dependencies []*compiler.Dependency
+		Args         []reflect.Value
+		Result       any
+		Error        error
+		Type         reflect.Type
+	}
+
+	var testCases = []TestCase{{
+		Constructor: func(a int, b string) (string, error) {
+			return b + strconv.Itoa(a), nil
+		},
+		Assisted: []int{1},
+		Dependencies: []*compiler.Dependency{{
+			Name:  "int",
+			Index: 0,
+			Type:  reflect.TypeOf(0),
+			Value: reflect.ValueOf(1),
+		}},
+		Args:   []reflect.Value{reflect.ValueOf("a")},
+		Result: "a1",
+		Type:   reflect.TypeOf((func(string) (string, error))(nil)),
+	}, {
+		Constructor: func(a int, b ...string) string {
+			return fmt.Sprint(a, b)
+		},
+		Assisted: []int{1},
+		Dependencies: []*compiler.Dependency{{
+			Name:  "int",
+			Index: 0,
+			Type:  reflect.TypeOf(0),
+			Value: reflect.ValueOf(1),
+		}},
+		Args:   []reflect.Value{reflect.ValueOf("a"), reflect.ValueOf("b")},
+		Result: "1 [a b]",
+		Type:   reflect.TypeOf((func(...string) string)(nil)),
+	}, {
+		Constructor: func(a int, b ...string) string {
+			return fmt.Sprint(a, b)
+		},
+		Assisted: []int{0},
+		Dependencies: []*compiler.Dependency{{
+			Name:  "",
+			Index: 1,
+			Type:  reflect.TypeOf([]string{}),
+			Value: reflect.ValueOf([]string{"a"}),
+		}},
+		Args:   []reflect.Value{reflect.ValueOf(2)},
+		Result: "2 [a]",
+		Type:   reflect.TypeOf((func(int) string)(nil)),
+	}, {
+		Constructor: func(a int) int {
+			return a
+		},
+		Assisted: []int{1},
+		Error:    compiler.ErrInvalidConstructor,
+	}, {
+		Constructor: nil,
+		Error:       compiler.ErrInvalidConstructor,
+	}}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("TestCase#%d", i+1), func(t *testing.T) {
+			var cmp, err = compiler.NewFactory(testCase.Constructor, testCase.Assisted)
+			if err != nil && errors.Is(err, testCase.Error) {
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.Type, cmp.Type())
+
+			var deps = cmp.Dependencies()
+			require.Len(t, deps, len(testCase.Dependencies))
+
+			for j := range deps {
+				require.Equal(t, testCase.Dependencies[j].Index, deps[j].Index)
+				require.Equal(t, testCase.Dependencies[j].Type, deps[j].Type)
+			}
+
+			var v, c, e = cmp.Create(testCase.Dependencies...)
+			require.Nil(t, c)
+			require.Nil(t, e)
+
+			var out = v.Call(testCase.Args)
+			require.Equal(t, testCase.Result, out[0].Interface())
+		})
+	}
+}
+
+func TestFactory_Panic(t *testing.T) {
+	var cmp, err = compiler.NewFactory(func(a int, b string) (string, error) {
+		panic("factory failed")
+	}, []int{1})
+
+	require.NoError(t, err)
+
+	var v, _, e = cmp.Create(&compiler.Dependency{Value: reflect.ValueOf(1)})
+	require.NoError(t, e)
+
+	var out = v.Call([]reflect.Value{reflect.ValueOf("a")})
+	require.Equal(t, "", out[0].Interface())
+	require.ErrorIs(t, out[1].Interface().(error), compiler.ErrPanicked)
+	require.ErrorContains(t, out[1].Interface().(error), "factory failed")
+
+	cmp, err = compiler.NewFactory(func(a int, b string) string {
+		panic("factory failed")
+	}, []int{1})
+
+	require.NoError(t, err)
+
+	v, _, e = cmp.Create(&compiler.Dependency{Value: reflect.ValueOf(1)})
+	require.NoError(t, e)
+	require.PanicsWithError(t, "unable to create because the constructor panicked : factory failed", func() {
+		v.Call([]reflect.Value{reflect.ValueOf("a")})
+	})
+}
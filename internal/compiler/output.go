@@ -0,0 +1,87 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package compiler
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Output implements the Compiler interface.
+type Output struct {
+	src   reflect.Type
+	typ   reflect.Type
+	index int
+}
+
+var (
+	// Output implements the Compiler interface.
+	_ Compiler = (*Output)(nil)
+
+	// ErrInvalidOutput is error triggered when provided invalid output.
+	ErrInvalidOutput = errors.New("invalid output")
+)
+
+// NewOutput is constructor of Output.
+//
+// Argument src must be the Outputs type or a struct type, the index argument is index of output or struct field.
+func NewOutput(src reflect.Type, index int) (*Output, error) {
+	var o = &Output{
+		src:   src,
+		index: index,
+	}
+
+	switch {
+	case src == nil || index < 0:
+		return nil, ErrInvalidOutput
+	case src == reflectOutputsType:
+		return nil, ErrInvalidOutput
+	case src.Kind() == reflect.Struct && index < src.NumField():
+		o.typ = src.Field(index).Type
+	default:
+		return nil, ErrInvalidOutput
+	}
+
+	return o, nil
+}
+
+// NewOutputs is constructor of Output for values created by the constructor with multiple outputs.
+func NewOutputs(typ reflect.Type, index int) (*Output, error) {
+	if typ == nil || index < 0 {
+		return nil, ErrInvalidOutput
+	}
+
+	return &Output{
+		src:   reflectOutputsType,
+		typ:   typ,
+		index: index,
+	}, nil
+}
+
+func (c *Output) Create(dependencies ...*Dependency) (reflect.Value, Closer, error) {
+	if len(dependencies) != 1 {
+		return reflect.Value{}, nil, ErrInvalidOutput
+	}
+
+	var v = dependencies[0].Value
+	if c.src == reflectOutputsType {
+		return v.Interface().(Outputs)[c.index], nil, nil
+	}
+
+	return v.Field(c.index), nil, nil
+}
+
+func (c *Output) Dependencies() []*Dependency {
+	return []*Dependency{{
+		Name:  c.src.Name(),
+		Index: 0,
+		Type:  c.src,
+		Value: reflect.New(c.src).Elem(),
+	}}
+}
+
+func (c *Output) Type() reflect.Type {
+	return c.typ
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package compiler_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gozix/di/internal/compiler"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutput(t *testing.T) {
+	type (
+		Result struct {
+			Public1 int
+			Public2 string
+		}
+
+		TestCase struct {
+			Source reflect.Type
+			Index  int
+			Value  reflect.Value
+			Result any
+			Error  error
+		}
+	)
+
+	var testCases = []TestCase{{
+		Source: reflect.TypeOf(Result{}),
+		Index:  1,
+		Value:  reflect.ValueOf(Result{Public1: 1, Public2: "2"}),
+		Result: "2",
+	}, {
+		Source: reflect.TypeOf(Result{}),
+		Index:  2,
+		Error:  compiler.ErrInvalidOutput,
+	}, {
+		Source: reflect.TypeOf(compiler.Outputs{}),
+		Index:  0,
+		Error:  compiler.ErrInvalidOutput,
+	}, {
+		Source: reflect.TypeOf(0),
+		Index:  0,
+		Error:  compiler.ErrInvalidOutput,
+	}, {
+		Source: nil,
+		Index:  0,
+		Error:  compiler.ErrInvalidOutput,
+	}}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("TestCase#%d", i+1), func(t *testing.T) {
+			var cmp, err = compiler.NewOutput(testCase.Source, testCase.Index)
+			if err != nil && errors.Is(err, testCase.Error) {
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, reflect.TypeOf(testCase.Result), cmp.Type())
+
+			var deps = cmp.Dependencies()
+			require.Len(t, deps, 1)
+			require.Equal(t, testCase.Source, deps[0].Type)
+
+			deps[0].Value = testCase.Value
+
+			var v, c, e = cmp.Create(deps...)
+			require.Equal(t, testCase.Result, v.Interface())
+			require.Nil(t, c)
+			require.Nil(t, e)
+		})
+	}
+}
+
+func TestOutputs(t *testing.T) {
+	var _, err = compiler.NewOutputs(nil, 0)
+	require.ErrorIs(t, err, compiler.ErrInvalidOutput)
+
+	var cmp *compiler.Output
+	cmp, err = compiler.NewOutputs(reflect.TypeOf(""), 1)
+	require.NoError(t, err)
+	require.Equal(t, reflect.TypeOf(""), cmp.Type())
+
+	var deps = cmp.Dependencies()
+	require.Len(t, deps, 1)
+	require.Equal(t, reflect.TypeOf(compiler.Outputs{}), deps[0].Type)
+
+	deps[0].Value = reflect.ValueOf(compiler.Outputs{reflect.ValueOf(1), reflect.ValueOf("2")})
+
+	var v, c, e = cmp.Create(deps...)
+	require.Equal(t, "2", v.Interface())
+	require.Nil(t, c)
+	require.Nil(t, e)
+}
@@ -0,0 +1,43 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package compiler
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// Typed implements the Compiler interface, it describes the constructor as Constructor does,
+	// but creates values by the typed call instead of reflect.Call.
+	Typed struct {
+		*Constructor
+		call TypedCall
+	}
+
+	// TypedCall calls the constructor function with dependency values directly.
+	TypedCall = func(dependencies []*Dependency) (reflect.Value, Closer, error)
+)
+
+// compile time check.
+var _ Compiler = (*Typed)(nil)
+
+// NewTyped is constructor of Typed.
+func NewTyped(ctor *Constructor, call TypedCall) *Typed {
+	return &Typed{
+		Constructor: ctor,
+		call:        call,
+	}
+}
+
+func (t *Typed) Create(dependencies ...*Dependency) (_ reflect.Value, _ Closer, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("unable to create because the constructor %w : %+v", ErrPanicked, recovered)
+		}
+	}()
+
+	return t.call(dependencies)
+}
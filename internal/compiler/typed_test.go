@@ -0,0 +1,51 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package compiler_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gozix/di/internal/compiler"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTyped(t *testing.T) {
+	var fn = func(v int) (string, func() error, error) {
+		if v < 0 {
+			panic("negative")
+		}
+
+		return "value", nil, nil
+	}
+
+	var ctor, err = compiler.NewConstructor(fn)
+	require.NoError(t, err)
+
+	var cmp = compiler.NewTyped(ctor, func(deps []*compiler.Dependency) (reflect.Value, compiler.Closer, error) {
+		var v, closer, err = fn(*deps[0].Value.Addr().Interface().(*int))
+		return reflect.ValueOf(v), closer, err
+	})
+
+	require.Equal(t, ctor.Type(), cmp.Type())
+	require.Len(t, cmp.Dependencies(), 1)
+	require.Equal(t, ctor.Dependencies()[0].Type, cmp.Dependencies()[0].Type)
+	require.True(t, cmp.Closer())
+
+	var deps = cmp.Dependencies()
+	deps[0].Value.SetInt(1)
+
+	var value reflect.Value
+	value, _, err = cmp.Create(deps...)
+	require.NoError(t, err)
+	require.Equal(t, "value", value.Interface())
+
+	deps[0].Value.SetInt(-1)
+
+	_, _, err = cmp.Create(deps...)
+	require.True(t, errors.Is(err, compiler.ErrPanicked))
+}
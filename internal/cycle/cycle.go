@@ -4,34 +4,44 @@
 
 package cycle
 
-// Cycle is cycle checker.
+// Cycle is cycle checker, the immutable stack of keys.
 type Cycle struct {
-	items map[int]bool
+	parent *Cycle
+	key    int
+	depth  int
 }
 
 // New is cycle constructor.
 func New() *Cycle {
-	return &Cycle{
-		items: map[int]bool{},
-	}
+	return &Cycle{}
 }
 
 // Append creates a new chain and adds the key to it
 func (c *Cycle) Append(key int) *Cycle {
-	var clone = &Cycle{
-		items: map[int]bool{},
+	return &Cycle{
+		parent: c,
+		key:    key,
+		depth:  c.depth + 1,
 	}
+}
 
-	for k, v := range c.items {
-		clone.items[k] = v
+// Has return true if the key exists
+func (c *Cycle) Has(key int) bool {
+	for item := c; item.depth > 0; item = item.parent {
+		if item.key == key {
+			return true
+		}
 	}
 
-	clone.items[key] = true
-
-	return clone
+	return false
 }
 
-// Has return true if the key exists
-func (c *Cycle) Has(key int) bool {
-	return c.items[key]
+// Path returns keys of the chain in the order they were appended.
+func (c *Cycle) Path() []int {
+	var path = make([]int, c.depth)
+	for item := c; item.depth > 0; item = item.parent {
+		path[item.depth-1] = item.key
+	}
+
+	return path
 }
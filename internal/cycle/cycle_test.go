@@ -15,9 +15,22 @@ func TestCycle(t *testing.T) {
 	var cl = cycle.New()
 
 	require.False(t, cl.Has(1))
+	require.Empty(t, cl.Path())
 
 	var c2 = cl.Append(1)
 
 	require.False(t, cl.Has(1))
 	require.True(t, c2.Has(1))
+
+	var (
+		c3 = c2.Append(2)
+		c4 = c2.Append(3)
+	)
+
+	require.True(t, c3.Has(1))
+	require.True(t, c3.Has(2))
+	require.False(t, c3.Has(3))
+	require.False(t, c4.Has(2))
+	require.Equal(t, []int{1, 2}, c3.Path())
+	require.Equal(t, []int{1, 3}, c4.Path())
 }
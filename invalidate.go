@@ -0,0 +1,301 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync/atomic"
+
+	"github.com/gozix/di/internal/cycle"
+	"github.com/gozix/di/internal/runtime"
+)
+
+// overlay is cache of instances recreated by Refresh, it is used instead of the container cache
+// until the recreated instances are merged.
+type overlay struct {
+	slots  map[int]*cacheSlot
+	merged int32
+}
+
+var (
+	// errEvicted is error of the evicted cache item, it is never returned, because acquire replaces such items.
+	errEvicted = errors.New("evicted")
+
+	// evicted is cache item published in place of the evicted one.
+	evicted = func() *cacheItem {
+		var item = &cacheItem{
+			err:   errEvicted,
+			ready: make(chan struct{}),
+		}
+
+		close(item.ready)
+
+		return item
+	}()
+)
+
+func (c *container) Invalidate(value Type, modifiers ...Modifier) (err error) {
+	if !c.begin() {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), ErrClosed)
+	}
+
+	defer c.end()
+
+	var ids map[int]bool
+	if ids, err = c.affected(value, modifiers); err != nil {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), err)
+	}
+
+	c.mux.Lock()
+	for id := range ids {
+		c.cache[id].evict()
+	}
+
+	var closers = c.detach(func(entry *closerEntry) bool {
+		return ids[entry.id]
+	})
+	c.mux.Unlock()
+
+	if err = c.close(closers); err != nil {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), err)
+	}
+
+	return nil
+}
+
+func (c *container) Refresh(value Type, modifiers ...Modifier) (err error) {
+	if !c.begin() {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), ErrClosed)
+	}
+
+	defer c.end()
+
+	var ids map[int]bool
+	if ids, err = c.affected(value, modifiers); err != nil {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), err)
+	}
+
+	var (
+		ov     = &overlay{slots: make(map[int]*cacheSlot, len(ids))}
+		order  = make([]int, 0, len(ids))
+		active = make(map[*closerEntry]bool)
+	)
+
+	var roots = make(map[int]bool)
+	for id := range ids {
+		if root := c.index[id].root; root != nil {
+			roots[root.id] = true
+		}
+	}
+
+	// constructors of outputs are recreated by resolution of the outputs, they have no type to resolve by
+	for id := range ids {
+		if c.cache[id].created() {
+			ov.slots[id] = &cacheSlot{}
+			if !roots[id] {
+				order = append(order, id)
+			}
+		}
+	}
+
+	sort.Ints(order)
+
+	c.mux.Lock()
+	for _, entry := range c.closers {
+		if ov.slots[entry.id] != nil {
+			active[entry] = true
+		}
+	}
+	c.mux.Unlock()
+
+	var ctn = &container{
+		containerCore: c.containerCore,
+		cycle:         cycle.New(),
+		overlay:       ov,
+		workers:       c.workers,
+	}
+
+	for _, id := range order {
+		var target = reflect.New(c.index[id].Type())
+		if err = c.resolve(ctn, &target, []Modifier{withID(id)}); err != nil {
+			break
+		}
+	}
+
+	c.mux.Lock()
+	var closers []*closerEntry
+	if err != nil {
+		closers = c.detach(func(entry *closerEntry) bool {
+			return entry.overlay == ov && (ov.slots[entry.id] != nil || c.index[entry.id].unshared)
+		})
+	} else {
+		for id, slot := range ov.slots {
+			c.cache[id].item.Store(slot.item.Load())
+		}
+
+		atomic.StoreInt32(&ov.merged, 1)
+		closers = c.detach(func(entry *closerEntry) bool {
+			return active[entry]
+		})
+	}
+
+	c.settle(ov, err != nil)
+	c.mux.Unlock()
+
+	var closeErr = c.close(closers)
+
+	switch {
+	case err != nil:
+		return fmt.Errorf("%s : unable to refresh : %w", runtime.Caller(0), err)
+	case closeErr != nil:
+		return fmt.Errorf("%s : %w", runtime.Caller(0), closeErr)
+	}
+
+	return nil
+}
+
+// affected returns ids of the shared definitions of the type and of all shared definitions depending on them.
+// Unshared definitions are walked through to reach the shared ones, but their instances are never affected.
+// Outputs of the constructor with several results affect the constructor and so each other.
+func (c *container) affected(value Type, modifiers []Modifier) (map[int]bool, error) {
+	var ch, err = c.target(value, modifiers)
+	if err != nil && !errors.Is(err, ErrMultipleDefinitions) {
+		return nil, NewTypeError(reflect.TypeOf(value), err)
+	}
+
+	if len(ch.defs) == 0 {
+		return nil, NewTypeError(reflect.TypeOf(value), ErrDoesNotExist)
+	}
+
+	var (
+		ids   = make(map[int]bool)
+		seen  = make(map[int]bool)
+		stack = make([]int, 0, len(ch.defs))
+	)
+
+	for _, def := range ch.defs {
+		stack = append(stack, def.id)
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for len(stack) > 0 {
+		var id = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		if !c.index[id].unshared {
+			ids[id] = true
+		}
+
+		if st := c.states[id]; st != nil {
+			for dependent := range st.dependents {
+				stack = append(stack, dependent)
+			}
+		}
+
+		// the output shares the instance of its constructor with the other outputs
+		if root := c.index[id].root; root != nil {
+			stack = append(stack, root.id)
+		}
+	}
+
+	return ids, nil
+}
+
+// detach removes matched closers and returns them, must be called under the lock.
+func (c *containerCore) detach(match func(entry *closerEntry) bool) (detached []*closerEntry) {
+	var kept = c.closers[:0]
+	for _, entry := range c.closers {
+		if match(entry) {
+			c.forget(entry)
+			detached = append(detached, entry)
+			continue
+		}
+
+		kept = append(kept, entry)
+	}
+
+	for i := len(kept); i < len(c.closers); i++ {
+		c.closers[i] = nil
+	}
+
+	c.closers = kept
+
+	return detached
+}
+
+// settle unmarks the entries created by the overlay, the entries of the failed overlay which are kept,
+// because they were not detached, are forgotten, must be called under the lock.
+func (c *containerCore) settle(ov *overlay, failed bool) {
+	for _, entry := range c.closers {
+		if entry.overlay == ov {
+			entry.overlay = nil
+		}
+	}
+
+	var stale []*closerEntry
+	for _, entries := range c.unshared {
+		for _, entry := range entries {
+			if entry.overlay != ov {
+				continue
+			}
+
+			entry.overlay = nil
+			if failed {
+				stale = append(stale, entry)
+			}
+		}
+	}
+
+	for _, entry := range stale {
+		c.forget(entry)
+	}
+}
+
+// close runs all closers in reverse order and returns the first error.
+func (c *containerCore) close(closers []*closerEntry) (err error) {
+	for i := len(closers) - 1; i >= 0; i-- {
+		atomic.AddUint64(&c.stats.closersRun, 1)
+		if e := closers[i].closer(); e != nil && err == nil {
+			err = fmt.Errorf("unable to close %s : %w", c.index[closers[i].id].Type(), e)
+		}
+	}
+
+	return err
+}
+
+// slot returns the cache slot of the definition.
+func (c *container) slot(id int) *cacheSlot {
+	if c.overlay != nil && atomic.LoadInt32(&c.overlay.merged) == 0 {
+		if slot, ok := c.overlay.slots[id]; ok {
+			return slot
+		}
+	}
+
+	return &c.cache[id]
+}
+
+// created checks that the slot contains created instance.
+func (s *cacheSlot) created() bool {
+	var item, _ = s.item.Load().(*cacheItem)
+	return item != nil && item.done() && item.err == nil
+}
+
+// evict replaces the created instance of the slot by the evicted item.
+func (s *cacheSlot) evict() {
+	var old = s.item.Load()
+	if item, _ := old.(*cacheItem); item != nil && item.done() && item.err == nil {
+		s.item.CompareAndSwap(old, evicted)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/gozix/di/internal/runtime"
+)
+
+type (
+	// Lazy is a dependency which is created on the first Get call.
+	//
+	// Constructors, autowired fields and functions passed to Container.Call may depend on Lazy[T], the container
+	// injects a handle bound to definitions of T restricted by the dependency constraint. The handle of an
+	// unshared definition creates its own instance, the handle of a shared definition returns the shared one.
+	//
+	//	func NewService(db di.Lazy[*sql.DB]) *Service {
+	//		return &Service{db: db}
+	//	}
+	Lazy[T any] struct {
+		handle *lazy
+	}
+
+	// lazy is Lazy handle.
+	lazy struct {
+		mux     sync.Mutex
+		resolve func() (any, error)
+		value   any
+		done    bool
+	}
+
+	// lazyBinder is Lazy type detector.
+	lazyBinder interface {
+		bind(handle *lazy)
+		elem() reflect.Type
+	}
+)
+
+var (
+	// Lazy implements the lazyBinder interface.
+	_ lazyBinder = (*Lazy[any])(nil)
+
+	// reflectLazyBinderType is lazyBinder reflect type cache.
+	reflectLazyBinderType = reflect.TypeOf((*lazyBinder)(nil)).Elem()
+)
+
+// Get returns the value, the value is created on the first call.
+func (l Lazy[T]) Get() (value T, err error) {
+	if l.handle == nil {
+		return value, fmt.Errorf("%s : %w", runtime.Caller(0), NewTypeError(l.elem(), ErrDoesNotExist))
+	}
+
+	var v any
+	if v, err = l.handle.get(); err != nil {
+		return value, fmt.Errorf("%s : %w", runtime.Caller(0), err)
+	}
+
+	value, _ = v.(T)
+
+	return value, nil
+}
+
+func (l *Lazy[T]) bind(handle *lazy) {
+	l.handle = handle
+}
+
+func (l *Lazy[T]) elem() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (l *lazy) get() (_ any, err error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if l.done {
+		return l.value, nil
+	}
+
+	if l.value, err = l.resolve(); err != nil {
+		return nil, err
+	}
+
+	l.done = true
+
+	return l.value, nil
+}
+
+// lazyElem returns type of the lazy dependency.
+func lazyElem(rt reflect.Type) (reflect.Type, bool) {
+	if rt.Kind() != reflect.Struct || !reflect.PointerTo(rt).Implements(reflectLazyBinderType) {
+		return nil, false
+	}
+
+	return reflect.New(rt).Interface().(lazyBinder).elem(), true
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gozix/di/internal/compiler"
+)
+
+// assistedOption is an option
+type assistedOption struct {
+	keys []any
+}
+
+// assistedOption implements the ProvideOption interface.
+var _ ProvideOption = (*assistedOption)(nil)
+
+// Assisted marks factory constructor parameters which are passed at runtime.
+//
+// The key argument may be int index of parameter, string name of parameter type, reflect.Type or any value
+// of the parameter type, for example:
+//   - di.Assisted(1)
+//   - di.Assisted("UserID")
+//   - di.Assisted(UserID(""))
+//
+// Assisted is only meaningful for Builder.Factory, other provide methods ignore it.
+func Assisted(keys ...any) ProvideOption {
+	return &assistedOption{
+		keys: keys,
+	}
+}
+
+func (o *assistedOption) applyProvideOption(def *definition) {
+	def.assisted = append(def.assisted, o.keys...)
+}
+
+// assisted returns indexes of the constructor parameters matched by the keys.
+func assisted(deps []*compiler.Dependency, keys []any) ([]int, error) {
+	var indexes = make([]int, 0, len(keys))
+	for _, key := range keys {
+		var found = false
+		for _, dep := range deps {
+			var matched bool
+			switch k := key.(type) {
+			case int:
+				matched = dep.Index == k
+			case string:
+				matched = dep.Name == k
+			case reflect.Type:
+				matched = dep.Type == k
+			default:
+				matched = dep.Type == reflect.TypeOf(key)
+			}
+
+			if matched {
+				indexes = append(indexes, dep.Index)
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("assisted parameter %v %w", key, ErrDoesNotExist)
+		}
+	}
+
+	return indexes, nil
+}
@@ -62,6 +62,63 @@ func Autowire(value Type, options ...ProvideOption) BuilderOption {
 	})
 }
 
+// DefaultOrder sets the modifier applied before any other modifiers to every slice resolution.
+// By default, slices are ordered by di.ByPriority(), nil modifier keeps definitions in the found order.
+func DefaultOrder(modifier Modifier) BuilderOption {
+	return builderOptionFunc(func(b *builder) error {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+
+		b.order = modifier
+
+		return nil
+	})
+}
+
+// Factory is builder constructor option.
+// This is a syntax sugar for builder constructor usage.
+func Factory(value Constructor, options ...ProvideOption) BuilderOption {
+	var option = caller(1)
+	return builderOptionFunc(func(b *builder) error {
+		return b.Factory(value, append([]ProvideOption{option}, options...)...)
+	})
+}
+
+// Strict enables strict mode, Build returns *ValidationError when definitions contain any of:
+//   - definitions of the same type with the same name and tags;
+//   - definitions depending on the Container;
+//   - constraints with the key matching no dependency;
+//   - optional dependencies which can never be satisfied, except slices and groups which may be empty;
+//   - unshared definitions returning closers;
+//   - shared definitions depending on unshared ones, see di.Warnings.
+func Strict() BuilderOption {
+	return builderOptionFunc(func(b *builder) error {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+
+		b.strict = true
+
+		return nil
+	})
+}
+
+// Warnings sets the handler of violations which are not errors without strict mode.
+//
+// Build reports to the handler every captive dependency, a shared definition depending on an unshared one
+// directly or through other shared definitions, which keeps the single unshared instance forever. The error
+// contains frames of the definitions on the path and wraps ErrCaptiveDependency. In strict mode the violations
+// are returned by Build instead.
+func Warnings(handler func(err error)) BuilderOption {
+	return builderOptionFunc(func(b *builder) error {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+
+		b.warn = handler
+
+		return nil
+	})
+}
+
 // Provide is builder constructor option.
 // This is a syntax sugar for builder constructor usage.
 func Provide(value Constructor, options ...ProvideOption) BuilderOption {
@@ -70,3 +127,21 @@ func Provide(value Constructor, options ...ProvideOption) BuilderOption {
 		return b.Provide(value, append([]ProvideOption{option}, options...)...)
 	})
 }
+
+// Replace is builder constructor option.
+// This is a syntax sugar for builder constructor usage, see Builder.Override.
+func Replace(target Type, value Constructor, options ...ProvideOption) BuilderOption {
+	var option = caller(1)
+	return builderOptionFunc(func(b *builder) error {
+		return b.Override(target, value, append([]ProvideOption{option}, options...)...)
+	})
+}
+
+// ProvideMethods is builder constructor option.
+// This is a syntax sugar for builder constructor usage.
+func ProvideMethods(value Value, options ...ProvideOption) BuilderOption {
+	var option = caller(1)
+	return builderOptionFunc(func(b *builder) error {
+		return b.ProvideMethods(value, append([]ProvideOption{option}, options...)...)
+	})
+}
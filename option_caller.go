@@ -6,12 +6,27 @@ package di
 
 import "github.com/gozix/di/internal/runtime"
 
-type callerOption struct {
-	frame runtime.Frame
-}
+type (
+	// CallerOption is both AddOption and ProvideOption.
+	CallerOption interface {
+		AddOption
+		ProvideOption
+	}
+
+	callerOption struct {
+		frame runtime.Frame
+	}
+)
+
+// callerOption implements the CallerOption interface.
+var _ CallerOption = (*callerOption)(nil)
 
-// callerOption implements the AsOption interface.
-var _ AddOption = (*callerOption)(nil)
+// Caller sets the frame reported by errors of the definition to the caller of the function calling Caller,
+// skip is the number of additional frames to ascend. Helpers wrapping builder options use it to point errors
+// at their callers instead of themselves.
+func Caller(skip int) CallerOption {
+	return caller(skip + 2)
+}
 
 func caller(skip int) *callerOption {
 	return &callerOption{
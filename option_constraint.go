@@ -104,6 +104,31 @@ func Sort(less func(a, b Definition) bool) Modifier {
 	}
 }
 
+// Named filters out definitions with another name.
+//
+// Unlike tags, the name is matched exactly.
+func Named(name string) Modifier {
+	return Filter(func(def Definition) bool {
+		return def.Name() == name
+	})
+}
+
+// ByPriority sorts the definitions by priority in descending order, definitions with the same priority keep
+// registration order.
+func ByPriority() Modifier {
+	return func(defs []Definition) []Definition {
+		sort.SliceStable(defs, func(i, j int) bool {
+			if defs[i].Priority() != defs[j].Priority() {
+				return defs[i].Priority() > defs[j].Priority()
+			}
+
+			return defs[i].ID() < defs[j].ID()
+		})
+
+		return defs
+	}
+}
+
 // WithTags filters out definitions without needed tags.
 func WithTags(tags ...string) Modifier {
 	return Filter(func(def Definition) bool {
@@ -120,6 +145,20 @@ func WithTags(tags ...string) Modifier {
 	})
 }
 
+// WithTagArg filters out definitions without needed tag arg.
+func WithTagArg(tag string, key string, value string) Modifier {
+	return WithTagArgMatch(tag, key, func(v string) bool {
+		return v == value
+	})
+}
+
+// WithTagArgMatch filters out definitions without tag arg matched the provided match function.
+func WithTagArgMatch(tag string, key string, match func(value string) bool) Modifier {
+	return Filter(func(def Definition) bool {
+		return def.Tags().match(tag, key, match)
+	})
+}
+
 // WithoutTags filters out definitions with needed tags.
 func WithoutTags(tags ...string) Modifier {
 	return Filter(func(def Definition) bool {
@@ -135,6 +174,13 @@ func WithoutTags(tags ...string) Modifier {
 	})
 }
 
+// withID filters out definitions with another identifier.
+func withID(id int) Modifier {
+	return Filter(func(def Definition) bool {
+		return def.ID() == id
+	})
+}
+
 func (m Modifier) applyRestriction(options *constraintOption) {
 	options.modifiers = append(options.modifiers, m)
 }
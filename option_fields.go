@@ -0,0 +1,22 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+// Fields are provide options of the particular fields of the di.Out struct keyed by field name.
+//
+// Fields are only meaningful for constructors which return a struct with embedded di.Out, other definitions
+// ignore them.
+//
+//	var builder = di.NewBuilder(
+//		di.Provide(NewClients, di.Fields{
+//			"Admin": {di.As(new(Admin))},
+//		}),
+//	)
+type Fields map[string][]ProvideOption
+
+// Fields implements the ProvideOption interface.
+var _ ProvideOption = (Fields)(nil)
+
+func (f Fields) applyProvideOption(_ *definition) {}
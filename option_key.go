@@ -0,0 +1,76 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// KeyBy sets the key of the definitions when a map with string keys is resolved.
+//
+// By default, the definitions are keyed by name, see di.KeyByName(). Definitions with empty key are skipped.
+//
+//	var handlers map[string]Handler
+//	container.Resolve(&handlers, di.KeyByTagArg("handler", "path"))
+func KeyBy(key func(def Definition) string) Modifier {
+	return func(defs []Definition) []Definition {
+		for i, def := range defs {
+			if d, ok := def.(*definition); ok {
+				var keyed = *d
+				keyed.key = key(def)
+				defs[i] = &keyed
+			}
+		}
+
+		return defs
+	}
+}
+
+// KeyByName sets the definition name as the key.
+func KeyByName() Modifier {
+	return KeyBy(func(def Definition) string {
+		return def.Name()
+	})
+}
+
+// KeyByTagArg sets value of the tag arg as the key.
+func KeyByTagArg(tag string, key string) Modifier {
+	return KeyBy(func(def Definition) string {
+		var t, _ = def.Tags().Find(tag)
+		var value, _ = t.Args.Value(key)
+
+		return value
+	})
+}
+
+// isKeyed checks that type is a map with string keys.
+func isKeyed(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Map && rt.Key().Kind() == reflect.String
+}
+
+// keys skips definitions without key and checks that keys are unique.
+func keys(defs []definition) ([]definition, error) {
+	var (
+		index  = 0
+		unique = make(map[string]*definition, len(defs))
+	)
+
+	for i := range defs {
+		if defs[i].key == "" {
+			continue
+		}
+
+		if dup, ok := unique[defs[i].key]; ok {
+			return nil, fmt.Errorf("%w %q : provided at %s and %s", ErrDuplicateKey, defs[i].key, dup.frame, defs[i].frame)
+		}
+
+		defs[index] = defs[i]
+		unique[defs[i].key] = &defs[index]
+		index++
+	}
+
+	return defs[:index], nil
+}
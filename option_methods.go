@@ -0,0 +1,22 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+// Methods are provide options of the particular methods keyed by method name.
+//
+// Methods are only meaningful for Builder.ProvideMethods, other provide methods ignore them.
+//
+//	var builder = di.NewBuilder(
+//		di.ProvideMethods(&Providers{}, di.Methods{
+//			"NewPrimaryDB": {di.Tags{{Name: "primary"}}},
+//			"NewReplicaDB": {di.Tags{{Name: "replica"}}},
+//		}),
+//	)
+type Methods map[string][]ProvideOption
+
+// Methods implements the ProvideOption interface.
+var _ ProvideOption = (Methods)(nil)
+
+func (m Methods) applyProvideOption(_ *definition) {}
@@ -0,0 +1,37 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+type (
+	// NameOption is an option
+	NameOption interface {
+		AddOption
+		ProvideOption
+	}
+
+	nameOption struct {
+		name string
+	}
+)
+
+// nameOption implements the NameOption interface.
+var _ NameOption = (*nameOption)(nil)
+
+// Name sets definition name.
+//
+// The name must be unique among definitions of the same type, use di.Named() modifier to resolve definition by name.
+func Name(name string) NameOption {
+	return &nameOption{
+		name: name,
+	}
+}
+
+func (o *nameOption) applyAddOption(def *definition) {
+	def.name = o.name
+}
+
+func (o *nameOption) applyProvideOption(def *definition) {
+	def.name = o.name
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"fmt"
+
+	"github.com/gozix/di/internal/runtime"
+)
+
+type (
+	// ParallelOption is both BuilderOption and ConstraintOption.
+	ParallelOption interface {
+		BuilderOption
+		ConstraintOption
+	}
+
+	// parallelOption is an option
+	parallelOption struct {
+		frame runtime.Frame
+		n     int
+	}
+)
+
+// parallelOption implements the ParallelOption interface.
+var _ ParallelOption = (*parallelOption)(nil)
+
+// Parallel enables concurrent resolution of sibling dependencies by up to n additional goroutines.
+//
+// Passed to the builder, the option applies to every resolution of the container. Passed to Call, it applies
+// to the resolution of the function arguments only, n goroutines are used instead of the container ones
+// and zero resolves the arguments sequentially. Definitions ignore the option.
+//
+// Shared definitions are still created once, cycles are still detected, and the error of the first
+// failed dependency in the declaration order is reported. Closers may be registered in any order.
+func Parallel(n int) ParallelOption {
+	return &parallelOption{
+		frame: runtime.Caller(0),
+		n:     n,
+	}
+}
+
+func (o *parallelOption) applyBuilderOption(b *builder) error {
+	if o.n < 0 {
+		return fmt.Errorf("%s : parallel %d %w", o.frame, o.n, ErrInvalidValue)
+	}
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.parallel = o.n
+
+	return nil
+}
+
+func (o *parallelOption) applyConstraintOption(_ constraints) {}
+
+func (o *parallelOption) applyProvideOption(_ *definition) {}
+
+// workers returns the pool of n workers, nil if the resolution is sequential.
+func (o *parallelOption) workers() chan struct{} {
+	if o.n == 0 {
+		return nil
+	}
+
+	return make(chan struct{}, o.n)
+}
@@ -0,0 +1,37 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+type (
+	// PriorityOption is an option
+	PriorityOption interface {
+		AddOption
+		ProvideOption
+	}
+
+	priorityOption struct {
+		value int
+	}
+)
+
+// priorityOption implements the PriorityOption interface.
+var _ PriorityOption = (*priorityOption)(nil)
+
+// Priority sets definition priority, by default all definitions have zero priority.
+//
+// Definitions with higher priority go first when a slice is resolved, see di.ByPriority().
+func Priority(value int) PriorityOption {
+	return &priorityOption{
+		value: value,
+	}
+}
+
+func (o *priorityOption) applyAddOption(def *definition) {
+	def.priority = o.value
+}
+
+func (o *priorityOption) applyProvideOption(def *definition) {
+	def.priority = o.value
+}
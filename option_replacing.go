@@ -0,0 +1,29 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+// replacingOption is an option
+type replacingOption struct {
+	modifiers []Modifier
+}
+
+// replacingOption implements the ProvideOption interface.
+var _ ProvideOption = (*replacingOption)(nil)
+
+// Replacing restricts definitions of the target type replaced by Builder.Override, by default all of them
+// are replaced.
+//
+// The option is only meaningful for Builder.Override, other provide methods ignore it.
+//
+//	var builder = di.NewBuilder(
+//		di.Replace((*sql.DB)(nil), NewFakeDB, di.Replacing(di.Named("replica"))),
+//	)
+func Replacing(modifiers ...Modifier) ProvideOption {
+	return &replacingOption{
+		modifiers: modifiers,
+	}
+}
+
+func (o *replacingOption) applyProvideOption(_ *definition) {}
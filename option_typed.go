@@ -0,0 +1,194 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"reflect"
+
+	"github.com/gozix/di/internal/compiler"
+)
+
+type (
+	// typedOption carries the typed call of the constructor provided by the typed helpers.
+	typedOption struct {
+		call compiler.TypedCall
+	}
+
+	// typedFunc is normalized typed constructor.
+	typedFunc[R any] func(deps []*compiler.Dependency) (R, compiler.Closer, error)
+)
+
+// typedOption implements the ProvideOption interface.
+var _ ProvideOption = (*typedOption)(nil)
+
+// Provide0 is builder constructor option of the function returning value without arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide0[R any](fn func() R, options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(_ []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r = fn()
+		return r, nil, nil
+	})
+}
+
+// Provide0E is builder constructor option of the function returning value and error without arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide0E[R any](fn func() (R, error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(_ []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r, err = fn()
+		return r, nil, err
+	})
+}
+
+// Provide0C is builder constructor option of the function returning value and closer without arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide0C[R any](fn func() (R, func() error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(_ []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r, closer = fn()
+		return r, closer, nil
+	})
+}
+
+// Provide0CE is builder constructor option of the function returning value, closer and error without arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide0CE[R any](fn func() (R, func() error, error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(_ []*compiler.Dependency) (R, compiler.Closer, error) {
+		return fn()
+	})
+}
+
+// Provide1 is builder constructor option of the function returning value from one argument.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide1[A, R any](fn func(A) R, options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r = fn(arg[A](deps[0]))
+		return r, nil, nil
+	})
+}
+
+// Provide1E is builder constructor option of the function returning value and error from one argument.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide1E[A, R any](fn func(A) (R, error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r, err = fn(arg[A](deps[0]))
+		return r, nil, err
+	})
+}
+
+// Provide1C is builder constructor option of the function returning value and closer from one argument.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide1C[A, R any](fn func(A) (R, func() error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r, closer = fn(arg[A](deps[0]))
+		return r, closer, nil
+	})
+}
+
+// Provide1CE is builder constructor option of the function returning value, closer and error from one argument.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide1CE[A, R any](fn func(A) (R, func() error, error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		return fn(arg[A](deps[0]))
+	})
+}
+
+// Provide2 is builder constructor option of the function returning value from two arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide2[A, B, R any](fn func(A, B) R, options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r = fn(arg[A](deps[0]), arg[B](deps[1]))
+		return r, nil, nil
+	})
+}
+
+// Provide2E is builder constructor option of the function returning value and error from two arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide2E[A, B, R any](fn func(A, B) (R, error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r, err = fn(arg[A](deps[0]), arg[B](deps[1]))
+		return r, nil, err
+	})
+}
+
+// Provide2C is builder constructor option of the function returning value and closer from two arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide2C[A, B, R any](fn func(A, B) (R, func() error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r, closer = fn(arg[A](deps[0]), arg[B](deps[1]))
+		return r, closer, nil
+	})
+}
+
+// Provide2CE is builder constructor option of the function returning value, closer and error from two arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide2CE[A, B, R any](fn func(A, B) (R, func() error, error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		return fn(arg[A](deps[0]), arg[B](deps[1]))
+	})
+}
+
+// Provide3 is builder constructor option of the function returning value from three arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide3[A, B, C, R any](fn func(A, B, C) R, options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r = fn(arg[A](deps[0]), arg[B](deps[1]), arg[C](deps[2]))
+		return r, nil, nil
+	})
+}
+
+// Provide3E is builder constructor option of the function returning value and error from three arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide3E[A, B, C, R any](fn func(A, B, C) (R, error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r, err = fn(arg[A](deps[0]), arg[B](deps[1]), arg[C](deps[2]))
+		return r, nil, err
+	})
+}
+
+// Provide3C is builder constructor option of the function returning value and closer from three arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide3C[A, B, C, R any](fn func(A, B, C) (R, func() error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		var r, closer = fn(arg[A](deps[0]), arg[B](deps[1]), arg[C](deps[2]))
+		return r, closer, nil
+	})
+}
+
+// Provide3CE is builder constructor option of the function returning value, closer and error from three arguments.
+// The function is called directly, without reflection, the options are the same as in Provide.
+func Provide3CE[A, B, C, R any](fn func(A, B, C) (R, func() error, error), options ...ProvideOption) BuilderOption {
+	return provideTyped(fn, caller(1), options, func(deps []*compiler.Dependency) (R, compiler.Closer, error) {
+		return fn(arg[A](deps[0]), arg[B](deps[1]), arg[C](deps[2]))
+	})
+}
+
+// provideTyped provides the constructor function with the typed call.
+func provideTyped[R any](fn any, option *callerOption, options []ProvideOption, call typedFunc[R]) BuilderOption {
+	var typed = &typedOption{
+		call: func(deps []*compiler.Dependency) (reflect.Value, compiler.Closer, error) {
+			var r, closer, err = call(deps)
+			return valueOf(r), closer, err
+		},
+	}
+
+	return builderOptionFunc(func(b *builder) error {
+		return b.Provide(fn, append([]ProvideOption{option, typed}, options...)...)
+	})
+}
+
+func (o *typedOption) applyProvideOption(_ *definition) {}
+
+// arg returns the dependency value, the dependency values are addressable, so no allocations are needed.
+func arg[T any](dep *compiler.Dependency) T {
+	return *dep.Value.Addr().Interface().(*T)
+}
+
+// valueOf returns the value of the exact type, even if it is an interface type.
+func valueOf[T any](v T) reflect.Value {
+	if reflect.TypeOf((*T)(nil)).Elem().Kind() == reflect.Interface {
+		return reflect.ValueOf(&v).Elem()
+	}
+
+	return reflect.ValueOf(v)
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Out is a marker of the constructor result struct.
+//
+// Every exported field of the struct that embeds Out becomes a separate definition, all of them share one
+// constructor call, the struct without exported fields is an invalid constructor result. The field definitions
+// may be customized with struct tags:
+//   - name:"primary" sets name of the field definition;
+//   - tags:"a,b" adds tags a and b to the field definition;
+//   - as:"Admin" gives the field definition the alias passed by di.As() to the constructor, the alias
+//     is referred by the interface name with or without the package, like Admin or api.Admin.
+//
+// The aliases passed by di.As() to the constructor go only to the fields tagged by them, the alias which
+// is not referred by any tag goes to all fields implementing it. Name and tags go to all fields.
+// Other provide options of the particular fields should be passed by di.Fields{}.
+//
+//	type Result struct {
+//		di.Out
+//
+//		Client *Client
+//		Admin  *AdminClient `tags:"admin" as:"Admin"`
+//	}
+type Out struct{}
+
+// reflectOutType is Out reflect type cache.
+var reflectOutType = reflect.TypeOf(Out{})
+
+// isOut checks that type is a struct which embeds Out.
+func isOut(rt reflect.Type) bool {
+	if rt.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < rt.NumField(); i++ {
+		if rt.Field(i).Anonymous && rt.Field(i).Type == reflectOutType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// outAliases parses aliases of the Out struct field.
+func outAliases(field reflect.StructField) (names []string) {
+	for _, name := range strings.Split(field.Tag.Get("as"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// outTags parses tags of the Out struct field.
+func outTags(field reflect.StructField) (tags Tags) {
+	for _, name := range strings.Split(field.Tag.Get("tags"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			tags = append(tags, Tag{Name: name})
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"errors"
+	"reflect"
+)
+
+// plan is precompiled resolution of the definition dependency. The definitions of the container never change
+// after Build, so the lookup, modifiers and constraints are applied only once.
+type plan struct {
+	choice     choice
+	constraint constraint
+	err        error
+	in         bool
+	fields     []plan
+	index      []int
+}
+
+// compile compiles resolution plans of every definition dependency and indexes definitions by id.
+func (c *containerCore) compile() {
+	var defs = c.defs.all()
+
+	c.plans = make(map[int][]plan, len(defs))
+	c.index = make(map[int]*definition, len(defs))
+	for i := range defs {
+		c.index[defs[i].id] = &defs[i]
+
+		var (
+			def  = &defs[i]
+			ctn  = &container{containerCore: c, def: def}
+			deps = def.compiler.Dependencies()
+		)
+
+		if def.root != nil {
+			c.plans[def.id] = []plan{{
+				choice: choice{typ: deps[0].Type, elem: deps[0].Type, defs: []definition{*def.root}},
+			}}
+
+			continue
+		}
+
+		var plans = make([]plan, 0, len(deps))
+		for _, dep := range deps {
+			if !isIn(dep.Type) {
+				plans = append(plans, ctn.plan(dep.Type, def.constraints.choose(dep.Index, dep.Name, dep.Type)))
+				continue
+			}
+
+			var p = plan{in: true}
+			for _, field := range inFields(dep.Type) {
+				var constr, err = inConstraint(field)
+				if err != nil {
+					p.err = NewTypeError(dep.Type, err)
+					break
+				}
+
+				var fp = ctn.plan(field.Type, constr)
+				fp.index = field.Index
+				p.fields = append(p.fields, fp)
+			}
+
+			plans = append(plans, p)
+		}
+
+		c.plans[def.id] = plans
+	}
+}
+
+// plan compiles resolution plan of the dependency type.
+func (c *container) plan(typ reflect.Type, constr constraint) plan {
+	var ch, err = c.choose(c, typ, constr.modifiers)
+	return plan{
+		choice:     ch,
+		constraint: constr,
+		err:        err,
+	}
+}
+
+// resolvePlan resolves the dependency by the plan.
+func (r *resolver) resolvePlan(ctn *container, v *reflect.Value, p *plan) error {
+	if p.in {
+		if p.err != nil {
+			return p.err
+		}
+
+		for i := range p.fields {
+			var fv = v.Elem().FieldByIndex(p.fields[i].index).Addr()
+			if err := r.resolvePlan(ctn, &fv, &p.fields[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if p.err != nil {
+		if errors.Is(p.err, ErrDoesNotExist) && p.constraint.optional {
+			return nil
+		}
+
+		return NewTypeError(v.Type(), p.err)
+	}
+
+	return r.apply(ctn, v, &p.choice, p.constraint.modifiers)
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import "reflect"
+
+// Provider is a function which resolves T on each call.
+//
+// Constructors, autowired fields and functions passed to Container.Call may depend on Provider[T] or on any
+// func() (T, error) type without own definitions, the container injects a function bound to definitions of T
+// restricted by the dependency constraint. Each call of the function bound to an unshared definition creates
+// a new instance, the function bound to a shared definition returns the shared one.
+//
+//	func NewHandler(newSession di.Provider[*Session]) *Handler {
+//		return &Handler{newSession: newSession}
+//	}
+type Provider[T any] func() (T, error)
+
+// providerElem returns type of the provider function result.
+func providerElem(rt reflect.Type) (reflect.Type, bool) {
+	if rt.Kind() != reflect.Func || rt.NumIn() != 0 || rt.NumOut() != 2 || rt.Out(1) != reflectErrorType {
+		return nil, false
+	}
+
+	return rt.Out(0), true
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gozix/di/internal/runtime"
+)
+
+// autoReleaseOption is an option
+type autoReleaseOption struct{}
+
+// autoReleaseOption implements the ConstraintOption interface.
+var _ ConstraintOption = (*autoReleaseOption)(nil)
+
+// AutoRelease releases unshared instances created for the Call arguments when the function returns.
+//
+// The option is meaningful only for Call, definitions ignore it.
+func AutoRelease() ConstraintOption {
+	return &autoReleaseOption{}
+}
+
+func (o *autoReleaseOption) applyConstraintOption(cs constraints) {
+	cs[autoReleaseOption{}] = constraint{}
+}
+
+func (o *autoReleaseOption) applyProvideOption(_ *definition) {}
+
+func (c *container) Release(value Value) (err error) {
+	if !c.begin() {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), ErrClosed)
+	}
+
+	defer c.end()
+
+	var rv = reflect.ValueOf(value)
+	if !identity(rv) {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), ErrInvalidValue)
+	}
+
+	var rt = rv.Type()
+
+	c.mux.Lock()
+	var entries = c.unshared[value]
+	if len(entries) == 0 {
+		c.mux.Unlock()
+		return fmt.Errorf("%s : %w", runtime.Caller(0), NewTypeError(rt, ErrDoesNotExist))
+	}
+
+	var closers = c.releaseEntries(entries[len(entries)-1])
+	c.mux.Unlock()
+
+	if err = c.close(closers); err != nil {
+		return fmt.Errorf("%s : %w", runtime.Caller(0), err)
+	}
+
+	return nil
+}
+
+// own records the unshared instance, so it can be released by value or together with the instance
+// depending on it, must be called under the lock.
+func (c *container) own(entry *closerEntry, sv reflect.Value) {
+	if c.owning {
+		c.owned = append(c.owned, entry)
+	}
+
+	if !sv.IsValid() || !sv.CanInterface() {
+		return
+	}
+
+	var key = sv.Interface()
+	if !identity(reflect.ValueOf(key)) {
+		return
+	}
+
+	entry.key = key
+	c.unshared[key] = append(c.unshared[key], entry)
+}
+
+// identity checks that the value is a non-nil pointer or channel, so it identifies the instance, values
+// of other kinds may be equal for distinct instances.
+func identity(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return !rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// releaseEntries forgets the entries with their owned subtrees and detaches their closers, must be called under the lock.
+func (c *containerCore) releaseEntries(entries ...*closerEntry) []*closerEntry {
+	var (
+		subtree = make(map[*closerEntry]bool)
+		stack   = append([]*closerEntry(nil), entries...)
+	)
+
+	for len(stack) > 0 {
+		var entry = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if subtree[entry] {
+			continue
+		}
+
+		subtree[entry] = true
+		c.forget(entry)
+		stack = append(stack, entry.owned...)
+	}
+
+	return c.detach(func(entry *closerEntry) bool {
+		return subtree[entry]
+	})
+}
+
+// forget removes the entry from the releasable unshared instances, must be called under the lock.
+func (c *containerCore) forget(entry *closerEntry) {
+	if entry.key == nil {
+		return
+	}
+
+	var entries = c.unshared[entry.key]
+	for i := range entries {
+		if entries[i] == entry {
+			entries = append(entries[:i], entries[i+1:]...)
+			break
+		}
+	}
+
+	if len(entries) == 0 {
+		delete(c.unshared, entry.key)
+	} else {
+		c.unshared[entry.key] = entries
+	}
+
+	entry.key = nil
+}
@@ -21,6 +21,9 @@ type (
 	}
 
 	// Tags is tag collection.
+	//
+	// A constructor or an autowired field of type Tags receives tags of its own definition, so the definition
+	// may use tag args as routing metadata.
 	Tags []Tag
 )
 
@@ -32,6 +35,28 @@ var (
 	_ ProvideOption = (*Tags)(nil)
 )
 
+// Value returns value of the arg by key.
+func (a Args) Value(key string) (string, bool) {
+	for _, arg := range a {
+		if arg.Key == key {
+			return arg.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Find returns the first tag by name.
+func (t Tags) Find(name string) (Tag, bool) {
+	for _, t1 := range t {
+		if t1.Name == name {
+			return t1, true
+		}
+	}
+
+	return Tag{}, false
+}
+
 func (t Tags) applyAddOption(def *definition) {
 	def.tags = append(def.tags, t...)
 }
@@ -41,11 +66,56 @@ func (t Tags) applyProvideOption(def *definition) {
 }
 
 func (t Tags) contains(name string) bool {
+	var _, ok = t.Find(name)
+	return ok
+}
+
+func (t Tags) match(name string, key string, match func(value string) bool) bool {
 	for _, t1 := range t {
-		if t1.Name == name {
+		if t1.Name != name {
+			continue
+		}
+
+		if value, ok := t1.Args.Value(key); ok && match(value) {
 			return true
 		}
 	}
 
 	return false
 }
+
+func (t Tags) equal(other Tags) bool {
+	if len(t) != len(other) {
+		return false
+	}
+
+	for _, t1 := range t {
+		var found = false
+		for _, t2 := range other {
+			if t1.Name == t2.Name && t1.Args.equal(t2.Args) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a Args) equal(other Args) bool {
+	if len(a) != len(other) {
+		return false
+	}
+
+	for _, arg := range a {
+		if value, ok := other.Value(arg.Key); !ok || value != arg.Value {
+			return false
+		}
+	}
+
+	return true
+}
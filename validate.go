@@ -0,0 +1,154 @@
+// Copyright 2022 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package di
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gozix/di/internal/compiler"
+)
+
+// reflectOutputsType is compiler.Outputs reflect type cache.
+var reflectOutputsType = reflect.TypeOf(compiler.Outputs(nil))
+
+// validate checks definitions in strict mode.
+func (b *builder) validate() error {
+	var errs []error
+	for _, def := range b.defs.all() {
+		if def.root == nil {
+			errs = append(errs, def.violations()...)
+		}
+
+		var typ = def.Type()
+		if typ == reflectOutputsType || isOut(typ) {
+			continue
+		}
+
+		for _, other := range b.defs[typ] {
+			if other.id < def.id && other.name == def.name && other.Type() == typ && other.tags.equal(def.tags) {
+				errs = append(errs, fmt.Errorf(
+					"%s : %w, already provided at %s", def.frame, NewTypeError(typ, ErrMultipleDefinitions), other.frame,
+				))
+			}
+		}
+	}
+
+	errs = append(errs, b.captives()...)
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+// isGroup checks that the type is a slice or a keyed map, which are resolved empty without definitions.
+func isGroup(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice || isKeyed(typ)
+}
+
+// violations returns violations of the definition dependencies, constraints and closer.
+func (d *definition) violations() (errs []error) {
+	var typ = d.Type()
+	for _, dep := range d.Dependencies() {
+		if reflectContainerType.AssignableTo(dep.Type) {
+			errs = append(errs, fmt.Errorf("%s : %w", d.frame, NewTypeError(typ, ErrContainerInjection)))
+			continue
+		}
+
+		if dep.Optional && len(dep.Definitions) == 0 && dep.Type != reflectTagsType && !isGroup(dep.Type) {
+			errs = append(errs, fmt.Errorf(
+				"%s : %w", d.frame, NewTypeError(typ, fmt.Errorf("optional %s %w", dep.Type, ErrDoesNotExist)),
+			))
+		}
+	}
+
+	for key := range d.constraints {
+		if !d.constrains(key) {
+			errs = append(errs, fmt.Errorf(
+				"%s : %w", d.frame, NewTypeError(typ, fmt.Errorf("constraint key %v %w", key, ErrDoesNotExist)),
+			))
+		}
+	}
+
+	if cmp, ok := d.compiler.(interface{ Closer() bool }); ok && d.unshared && cmp.Closer() {
+		errs = append(errs, fmt.Errorf("%s : %w", d.frame, NewTypeError(typ, ErrUnsharedCloser)))
+	}
+
+	return errs
+}
+
+// captives checks that definitions do not capture dependencies living shorter than themselves.
+//
+// The shared instance lives as long as the container, the unshared one lives as long as its dependent. The shared
+// definition depending on the unshared one keeps the single instance forever, including through di.Lazy and
+// factories, only provider functions create a new instance on each call. The lifetime is effective, so
+// the shared definition depending on the unshared one through other shared definitions is reported as well.
+func (b *builder) captives() (errs []error) {
+	var (
+		origins = make(map[int]*definition)
+		visited = make(map[int]bool)
+		origin  func(def *definition) *definition
+	)
+
+	// origin returns the unshared definition shortening the effective lifetime of the definition, if any
+	origin = func(def *definition) *definition {
+		if def.lifetime() == lifetimeUnshared {
+			return def
+		}
+
+		if visited[def.id] {
+			return origins[def.id]
+		}
+
+		visited[def.id] = true
+		for _, od := range b.captured(def) {
+			if o := origin(od); o != nil {
+				origins[def.id] = o
+				return o
+			}
+		}
+
+		return nil
+	}
+
+	for _, def := range b.defs.list() {
+		if def.lifetime() == lifetimeUnshared {
+			continue
+		}
+
+		for _, od := range b.captured(&def) {
+			var o = origin(od)
+			if o == nil {
+				continue
+			}
+
+			var reason = fmt.Errorf("%w on %s %s provided at %s", ErrCaptiveDependency, o.lifetime(), o.Type(), o.frame)
+			if o.id != od.id {
+				reason = fmt.Errorf("%w through %s provided at %s", reason, od.Type(), od.frame)
+			}
+
+			errs = append(errs, fmt.Errorf("%s : %w", def.frame, NewTypeError(def.Type(), reason)))
+		}
+	}
+
+	return errs
+}
+
+// captured returns definitions the definition instance keeps, dependencies on provider functions are skipped.
+func (b *builder) captured(def *definition) (defs []*definition) {
+	for _, dep := range def.Dependencies() {
+		if _, ok := providerElem(dep.Type); ok && len(b.defs[dep.Type]) == 0 {
+			continue
+		}
+
+		for _, other := range dep.Definitions {
+			defs = append(defs, other.(*definition))
+		}
+	}
+
+	return defs
+}